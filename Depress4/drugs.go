@@ -0,0 +1,241 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/emergent/params"
+	"github.com/emer/etable/etable"
+	"github.com/emer/leabra/leabra"
+)
+
+// Drug is one pharmacological manipulation of a schedule, read from one row
+// of a drug table (e.g., Drugs.tsv), which has these columns:
+//   - Name: name of the drug (e.g., SSRI, Ketamine)
+//   - Sel: name of the projection (e.g., #VTAToApproach) or layer (e.g., #VTA)
+//   - Param: parameter, as in the ParamSets: Prjn.WtScale.Abs, Layer.Act.Gbar.L,
+//     Layer.Act.Noise.Var, etc
+//   - Scale: time scale of Start, Onset, Dur and Offset: Epoch (of training)
+//     or Tick (time step of the World) -- default Tick (optional)
+//   - Start: time when the drug starts to take effect
+//   - Onset: time for the level of the drug to ramp in to the full Dose
+//   - Dur: time at the full Dose
+//   - Offset: time for the level of the drug to ramp out to zero
+//   - Dose: full dose, in units of the dose for half the maximum effect (EC50)
+//   - Hill: Hill coefficient of the dose-response curve, > 0 -- default 1 (optional)
+//   - Emax: maximum effect, as a proportion of the baseline value of the
+//     parameter (e.g., -0.5 halves it at a very high dose)
+//
+// The effects of all the drugs on the same parameter are added together.
+type Drug struct {
+	Name   string  `desc:"name of the drug"`
+	Sel    string  `desc:"name of the projection or layer, with a # prefix"`
+	Param  string  `desc:"parameter path, starting with Prjn. or Layer."`
+	Scale  string  `desc:"time scale: Epoch or Tick"`
+	Start  float64 `desc:"time when the drug starts to take effect"`
+	Onset  float64 `desc:"time to ramp in to the full Dose"`
+	Dur    float64 `desc:"time at the full Dose"`
+	Offset float64 `desc:"time to ramp out to zero"`
+	Dose   float64 `desc:"full dose, in units of EC50"`
+	Hill   float64 `desc:"Hill coefficient of the dose-response curve"`
+	Emax   float64 `desc:"maximum effect, as a proportion of the baseline value of the parameter"`
+	Resp   float64 `inactive:"+" desc:"current response, from 0 to 1, as a proportion of Emax"`
+}
+
+// Level returns the level of the drug at given time, from 0 to Dose
+func (dr *Drug) Level(t float64) float64 {
+	t -= dr.Start
+	switch {
+	case t < 0:
+		return 0
+	case t < dr.Onset:
+		return dr.Dose * t / dr.Onset
+	case t < dr.Onset+dr.Dur:
+		return dr.Dose
+	case t < dr.Onset+dr.Dur+dr.Offset:
+		return dr.Dose * (1 - (t-dr.Onset-dr.Dur)/dr.Offset)
+	}
+	return 0
+}
+
+// Response returns the response to the level of the drug at given time,
+// from the dose-response curve: L^Hill / (1 + L^Hill), with L in units of EC50
+// -- 0 when there is none of the drug
+func (dr *Drug) Response(t float64) float64 {
+	lv := dr.Level(t)
+	if lv <= 0 {
+		return 0
+	}
+	lv = math.Pow(lv, dr.Hill)
+	return lv / (1 + lv)
+}
+
+// Key returns the name of the parameter of the drug, for combining drugs
+func (dr *Drug) Key() string {
+	return dr.Sel + " " + dr.Param
+}
+
+// DrugSched is a schedule of pharmacological manipulations of projection and
+// layer parameters, which ramp in and out over the simulated time, e.g., slow
+// onset SSRI-like effects on #VTAToApproach versus rapid ketamine-like effects
+// on #DyDAToVTA within one run.  The baseline value of each parameter is
+// recorded before the first drug changes it, and set back by Restore.
+type DrugSched struct {
+	Table *etable.Table      `view:"no-inline" desc:"schedule of the drugs, one per row -- see Drug for the columns"`
+	Drugs []*Drug            `desc:"drugs from the rows of the Table"`
+	bases map[string]float64 // baseline value of each parameter
+}
+
+// SetTable sets the schedule from the rows of the given drug table
+func (ds *DrugSched) SetTable(dt *etable.Table) error {
+	for _, cnm := range []string{"Name", "Sel", "Param", "Start", "Onset", "Dur", "Offset", "Dose", "Emax"} {
+		if dt.ColByName(cnm) == nil {
+			return fmt.Errorf("DrugSched: drug table has no %v column", cnm)
+		}
+	}
+	ds.Table = dt
+	ds.Drugs = make([]*Drug, dt.Rows)
+	for row := 0; row < dt.Rows; row++ {
+		dr := &Drug{Scale: "Tick", Hill: 1}
+		dr.Name = dt.CellString("Name", row)
+		dr.Sel = dt.CellString("Sel", row)
+		dr.Param = dt.CellString("Param", row)
+		if dt.ColByName("Scale") != nil {
+			dr.Scale = dt.CellString("Scale", row)
+		}
+		dr.Start = dt.CellFloat("Start", row)
+		dr.Onset = dt.CellFloat("Onset", row)
+		dr.Dur = dt.CellFloat("Dur", row)
+		dr.Offset = dt.CellFloat("Offset", row)
+		dr.Dose = dt.CellFloat("Dose", row)
+		if dt.ColByName("Hill") != nil {
+			dr.Hill = dt.CellFloat("Hill", row)
+		}
+		dr.Emax = dt.CellFloat("Emax", row)
+		if dr.Scale != "Epoch" && dr.Scale != "Tick" {
+			return fmt.Errorf("DrugSched: drug %v in row %v has an invalid Scale: %v -- must be Epoch or Tick", dr.Name, row, dr.Scale)
+		}
+		if dr.Hill <= 0 {
+			return fmt.Errorf("DrugSched: drug %v in row %v has an invalid Hill: %v -- must be > 0", dr.Name, row, dr.Hill)
+		}
+		ds.Drugs[row] = dr
+	}
+	ds.bases = nil
+	return nil
+}
+
+// Validate checks that the parameter of each drug exists in the network
+func (ds *DrugSched) Validate(net emer.Network) error {
+	for _, dr := range ds.Drugs {
+		obj, path, err := DrugParam(net, dr.Sel, dr.Param)
+		if err == nil {
+			_, err = params.GetParam(obj, path)
+		}
+		if err != nil {
+			return fmt.Errorf("DrugSched: drug %v: %v", dr.Name, err)
+		}
+	}
+	return nil
+}
+
+// Apply sets each parameter changed by the drugs of the given time scale
+// to its value at given time, and updates the parameters derived from them,
+// and returns the maximum Response over those drugs
+func (ds *DrugSched) Apply(net emer.Network, scale string, t float64) float64 {
+	if ds.bases == nil {
+		ds.bases = make(map[string]float64)
+	}
+	effs := make(map[string]float64)
+	var keys []*Drug
+	var max float64
+	for _, dr := range ds.Drugs {
+		if dr.Scale != scale {
+			continue
+		}
+		dr.Resp = dr.Response(t)
+		if dr.Resp > max {
+			max = dr.Resp
+		}
+		key := dr.Key()
+		if _, has := effs[key]; !has {
+			keys = append(keys, dr)
+		}
+		effs[key] += dr.Emax * dr.Resp
+	}
+	for _, dr := range keys {
+		obj, path, err := DrugParam(net, dr.Sel, dr.Param)
+		if err != nil {
+			continue
+		}
+		key := dr.Key()
+		base, has := ds.bases[key]
+		if !has {
+			if base, err = params.GetParam(obj, path); err != nil {
+				continue
+			}
+			ds.bases[key] = base
+		}
+		params.SetParam(obj, path, fmt.Sprintf("%g", base*(1+effs[key])))
+	}
+	if len(keys) > 0 {
+		net.UpdateParams()
+	}
+	return max
+}
+
+// Restore sets each parameter changed by the drugs back to its baseline value,
+// and updates the parameters derived from them
+func (ds *DrugSched) Restore(net emer.Network) {
+	restored := false
+	for _, dr := range ds.Drugs {
+		dr.Resp = 0
+		base, has := ds.bases[dr.Key()]
+		if !has {
+			continue
+		}
+		if obj, path, err := DrugParam(net, dr.Sel, dr.Param); err == nil {
+			params.SetParam(obj, path, fmt.Sprintf("%g", base))
+			restored = true
+		}
+	}
+	if restored {
+		net.UpdateParams()
+	}
+}
+
+// Reset forgets the baseline values, e.g., after the parameters have been set
+// from the ParamSets, which are then the new baselines
+func (ds *DrugSched) Reset() {
+	ds.bases = nil
+}
+
+// DrugParam returns the projection or layer with given #name selector, and the
+// path of the given Prjn. or Layer. parameter within it
+func DrugParam(net emer.Network, sel, param string) (interface{}, string, error) {
+	nm := strings.TrimPrefix(sel, "#")
+	switch {
+	case strings.HasPrefix(param, "Prjn."):
+		for li := 0; li < net.NLayers(); li++ {
+			ly := net.Layer(li)
+			for pi := 0; pi < ly.NRecvPrjns(); pi++ {
+				if pj := ly.RecvPrjn(pi); pj.Name() == nm {
+					return pj.(leabra.LeabraPrjn).AsLeabra(), strings.TrimPrefix(param, "Prjn."), nil
+				}
+			}
+		}
+		return nil, "", fmt.Errorf("projection not found: %v", sel)
+	case strings.HasPrefix(param, "Layer."):
+		ly, err := net.LayerByNameTry(nm)
+		if err != nil {
+			return nil, "", err
+		}
+		return ly.(leabra.LeabraLayer).AsLeabra(), strings.TrimPrefix(param, "Layer."), nil
+	}
+	return nil, "", fmt.Errorf("parameter must start with Prjn. or Layer.: %v", param)
+}
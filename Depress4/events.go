@@ -0,0 +1,91 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// GenWorldChanges fills the dt table with nticks rows of exogenous Changes in
+// the given layer, in the WorldChanges format, sampled from the events table,
+// which has one row per unit of the layer with these columns:
+//   - Name: name of the feature
+//   - Rate: mean number of arrivals per time step (Poisson) while absent
+//   - Val: value of the feature when it enters
+//   - Dur: mean number of time steps the feature stays -- 0 = stays until the end
+//
+// A feature enters with its Val and leaves with -1, as in hand-written tables.
+// The other columns of dt, e.g., the InteroState of a hand-written WorldChanges
+// table that it replaces, are kept, with no Changes, so that it has every column
+// of the hand-written tables.  The start tensor (e.g., the first row of the World) has the features present
+// at the start, which can be nil.  The same seed always generates the same Changes.
+func GenWorldChanges(dt, events *etable.Table, lay string, nticks int, seed int64, start etensor.Tensor) error {
+	for _, cnm := range []string{"Rate", "Val", "Dur"} {
+		if events.ColByName(cnm) == nil {
+			return fmt.Errorf("GenWorldChanges: events table has no %v column", cnm)
+		}
+	}
+	if nticks < 1 {
+		return fmt.Errorf("GenWorldChanges: nticks must be positive: %v", nticks)
+	}
+	nf := events.Rows
+	sch := etable.Schema{
+		{"Name", etensor.STRING, nil, nil},
+		{lay, etensor.FLOAT32, []int{1, nf}, []string{"Y", "X"}},
+	}
+	for _, cl := range dt.Schema() {
+		if cl.Name != "Name" && cl.Name != lay {
+			sch = append(sch, cl)
+		}
+	}
+	dt.SetFromSchema(sch, nticks)
+	dt.SetMetaData("name", "WorldChanges")
+	dt.SetMetaData("desc", fmt.Sprintf("generated exogenous Changes in the World, seed: %v", seed))
+
+	nms := events.ColByName("Name")
+	rnd := rand.New(rand.NewSource(seed))
+	present := make([]bool, nf)
+	if start != nil {
+		for i := range present {
+			if i < start.Len() {
+				present[i] = start.FloatVal1D(i) > 0
+			}
+		}
+	}
+	dt.SetCellString("Name", 0, "Start") // first row is the starting state
+	for t := 1; t < nticks; t++ {
+		var evs []string
+		chg := dt.CellTensor(lay, t)
+		for i := 0; i < nf; i++ {
+			fnm := fmt.Sprintf("%v", i)
+			if nms != nil {
+				fnm = nms.StringVal1D(i)
+			}
+			if present[i] {
+				dur := events.CellFloat("Dur", i)
+				if dur > 0 && rnd.Float64() < 1/dur {
+					present[i] = false
+					chg.SetFloat1D(i, -1)
+					evs = append(evs, fnm+"-")
+				}
+				continue
+			}
+			rate := events.CellFloat("Rate", i)
+			if rate > 0 && rnd.Float64() < 1-math.Exp(-rate) {
+				present[i] = true
+				chg.SetFloat1D(i, events.CellFloat("Val", i))
+				evs = append(evs, fnm+"+")
+			}
+		}
+		dt.SetCellString("Name", t, strings.Join(evs, " "))
+	}
+	return nil
+}
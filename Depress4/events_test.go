@@ -0,0 +1,184 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// testEvents returns an events table with one row per feature, with the
+// given Rate, Val and Dur
+func testEvents(rates, vals, durs []float64) *etable.Table {
+	sch := etable.Schema{
+		{"Name", etensor.STRING, nil, nil},
+		{"Rate", etensor.FLOAT64, nil, nil},
+		{"Val", etensor.FLOAT64, nil, nil},
+		{"Dur", etensor.FLOAT64, nil, nil},
+	}
+	dt := etable.New(sch, len(rates))
+	for i := range rates {
+		dt.SetCellString("Name", i, string(rune('A'+i)))
+		dt.SetCellFloat("Rate", i, rates[i])
+		dt.SetCellFloat("Val", i, vals[i])
+		dt.SetCellFloat("Dur", i, durs[i])
+	}
+	return dt
+}
+
+// countChanges returns the number of entries and exits of feature i
+func countChanges(dt *etable.Table, i int) (enter, exit int) {
+	for t := 0; t < dt.Rows; t++ {
+		switch v := dt.CellTensor("EnviroFeatures", t).FloatVal1D(i); {
+		case v > 0:
+			enter++
+		case v < 0:
+			exit++
+		}
+	}
+	return
+}
+
+func TestGenWorldChangesErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		events *etable.Table
+		nticks int
+	}{
+		{"no Rate column", etable.New(etable.Schema{{"Val", etensor.FLOAT64, nil, nil}, {"Dur", etensor.FLOAT64, nil, nil}}, 1), 10},
+		{"zero ticks", testEvents([]float64{1}, []float64{1}, []float64{1}), 0},
+	}
+	for _, tt := range tests {
+		if err := GenWorldChanges(&etable.Table{}, tt.events, "EnviroFeatures", tt.nticks, 1, nil); err == nil {
+			t.Errorf("%v: expected an error", tt.name)
+		}
+	}
+}
+
+func TestGenWorldChanges(t *testing.T) {
+	tests := []struct {
+		name        string
+		rate, val   float64
+		dur         float64
+		start       float32
+		enter, exit int // -1 = any number
+	}{
+		{"never arrives", 0, 1, 0, 0, 0, 0},
+		{"arrives once and stays", 100, 0.5, 0, 0, 1, 0},
+		{"present at the start and stays", 100, 1, 0, 1, 0, 0},
+		{"comes and goes", 0.5, 1, 2, 0, -1, -1},
+	}
+	rates, vals, durs := make([]float64, len(tests)), make([]float64, len(tests)), make([]float64, len(tests))
+	start := etensor.NewFloat32([]int{len(tests)}, nil, nil)
+	for i, tt := range tests {
+		rates[i], vals[i], durs[i] = tt.rate, tt.val, tt.dur
+		start.Values[i] = tt.start
+	}
+	events := testEvents(rates, vals, durs)
+	dt := &etable.Table{}
+	if err := GenWorldChanges(dt, events, "EnviroFeatures", 200, 1, start); err != nil {
+		t.Fatal(err)
+	}
+	if dt.Rows != 200 {
+		t.Fatalf("rows: got %v, want 200", dt.Rows)
+	}
+	for i, tt := range tests {
+		enter, exit := countChanges(dt, i)
+		if tt.enter >= 0 && enter != tt.enter {
+			t.Errorf("%v: entries: got %v, want %v", tt.name, enter, tt.enter)
+		}
+		if tt.exit >= 0 && exit != tt.exit {
+			t.Errorf("%v: exits: got %v, want %v", tt.name, exit, tt.exit)
+		}
+		present := tt.start > 0 // entries and exits alternate, with the Val on entry
+		for r := 0; r < dt.Rows; r++ {
+			v := dt.CellTensor("EnviroFeatures", r).FloatVal1D(i)
+			switch {
+			case v > 0 && (present || v != tt.val):
+				t.Errorf("%v: row %v: entry %v while present: %v", tt.name, r, v, present)
+			case v < 0 && (!present || v != -1):
+				t.Errorf("%v: row %v: exit %v while absent", tt.name, r, v)
+			}
+			if v != 0 {
+				present = v > 0
+			}
+		}
+	}
+}
+
+func TestGenWorldChangesSeed(t *testing.T) {
+	events := testEvents([]float64{0.3, 0.1}, []float64{1, 0.5}, []float64{3, 5})
+	gen := func(seed int64) *etable.Table {
+		dt := &etable.Table{}
+		if err := GenWorldChanges(dt, events, "EnviroFeatures", 100, seed, nil); err != nil {
+			t.Fatal(err)
+		}
+		return dt
+	}
+	a, b, c := gen(5), gen(5), gen(6)
+	same := func(x, y *etable.Table) bool {
+		for r := 0; r < x.Rows; r++ {
+			if x.CellString("Name", r) != y.CellString("Name", r) {
+				return false
+			}
+		}
+		return true
+	}
+	if !same(a, b) {
+		t.Errorf("the same seed generated different Changes")
+	}
+	if same(a, c) {
+		t.Errorf("different seeds generated the same Changes")
+	}
+}
+
+// TestGenWorldChangesRate checks the Poisson arrival rate: with Dur 1, a
+// feature leaves on the step after it enters, and then enters again after a
+// geometric number of steps, with probability 1 - exp(-Rate) per step
+func TestGenWorldChangesRate(t *testing.T) {
+	tests := []float64{0.05, 0.1, 0.5}
+	nticks := 20000
+	for _, rate := range tests {
+		events := testEvents([]float64{rate}, []float64{1}, []float64{1})
+		dt := &etable.Table{}
+		if err := GenWorldChanges(dt, events, "EnviroFeatures", nticks, 1, nil); err != nil {
+			t.Fatal(err)
+		}
+		enter, _ := countChanges(dt, 0)
+		p := 1 - math.Exp(-rate)
+		want := float64(nticks-1) / (1/p + 1)
+		if math.Abs(float64(enter)-want) > 0.1*want {
+			t.Errorf("rate %v: entries: got %v, want about %.0f", rate, enter, want)
+		}
+	}
+}
+
+func TestGenWorldChangesKeepsColumns(t *testing.T) {
+	sch := etable.Schema{
+		{"Name", etensor.STRING, nil, nil},
+		{"EnviroFeatures", etensor.FLOAT32, []int{1, 2}, []string{"Y", "X"}},
+		{"InteroState", etensor.FLOAT32, []int{1, 3}, []string{"Y", "X"}},
+	}
+	dt := etable.New(sch, 5) // e.g., a hand-written WorldChanges table
+	events := testEvents([]float64{1, 1}, []float64{1, 1}, []float64{2, 2})
+	if err := GenWorldChanges(dt, events, "EnviroFeatures", 10, 1, nil); err != nil {
+		t.Fatal(err)
+	}
+	cl := dt.ColByName("InteroState")
+	if cl == nil {
+		t.Fatalf("InteroState column was not kept: %v", dt.ColNames)
+	}
+	if n := cl.Len() / cl.Dim(0); n != 3 || dt.Rows != 10 {
+		t.Errorf("InteroState: got %v units and %v rows, want 3 and 10", n, dt.Rows)
+	}
+	for r := 0; r < dt.Rows; r++ {
+		if v := dt.CellTensor("InteroState", r).FloatVal1D(0); v != 0 {
+			t.Errorf("InteroState row %v: got %v, want no Changes", r, v)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// HelplessParams are the parameters of the learned helplessness protocol in
+// the closed-loop World.  Each episode is an aversive event: the Shock unit of
+// EnviroFeatures (Danger) and the Fear unit of InteroState are turned on.
+// In the Yoked phase, the event lasts for MaxLat time steps whatever Behavior
+// is chosen, so that the Escape Behaviors only incur their Cost, while Stress
+// accumulates from the danger and the Avoidance activity.  In the Test phase,
+// the event ends as soon as an Escape Behavior is chosen, which is reinforced
+// by a training trial, and the escape latency of each episode is logged.
+type HelplessParams struct {
+	Shock    int     `def:"6" desc:"index of the EnviroFeatures unit that is on during the aversive event (Dngr)"`
+	Fear     int     `def:"6" desc:"index of the InteroState unit that is on during the aversive event (Fear)"`
+	Escape   []int   `desc:"indexes of the Behaviors that end the aversive event in the Test phase (Lve, SLve)"`
+	YokedEps int     `def:"20" desc:"number of episodes in the Yoked phase -- 0 = control, with no inescapable events"`
+	TestEps  int     `def:"20" desc:"number of episodes in the Test phase"`
+	MaxLat   int     `def:"10" desc:"number of time steps of an event in the Yoked phase, and the maximum latency in the Test phase"`
+	ITI      int     `def:"3" desc:"number of time steps between episodes, with no aversive event"`
+	Learn    bool    `def:"true" desc:"if true, a training trial with the chosen Escape Behavior as the target follows each escape in the Test phase"`
+	On       bool    `inactive:"+" desc:"true while the aversive event is on"`
+	Latency  float32 `inactive:"+" desc:"mean escape latency over the Test episodes, in time steps -- MaxLat when not escaped"`
+	Rate     float32 `inactive:"+" desc:"avoidance learning rate: decrease in escape latency per Test episode, from the least-squares slope over the episodes"`
+}
+
+func (hp *HelplessParams) Defaults() {
+	hp.Shock = 6
+	hp.Fear = 6
+	hp.Escape = []int{12, 13}
+	hp.YokedEps = 20
+	hp.TestEps = 20
+	hp.MaxLat = 10
+	hp.ITI = 3
+	hp.Learn = true
+}
+
+// IsEscape returns whether the given Behavior is an Escape Behavior
+func (hp *HelplessParams) IsEscape(beh int) bool {
+	for _, eb := range hp.Escape {
+		if eb == beh {
+			return true
+		}
+	}
+	return false
+}
+
+// SetEvent turns the aversive event on or off in the State of the World,
+// and in the State observed by the network
+func (hp *HelplessParams) SetEvent(ev *WorldEnv, on bool) {
+	hp.On = on
+	var v float32
+	if on {
+		v = 1
+	}
+	for lnm, ui := range map[string]int{"EnviroFeatures": hp.Shock, "InteroState": hp.Fear} {
+		for _, st := range []*etensor.Float32{ev.States[lnm], ev.Obs[lnm]} {
+			if st != nil && ui < len(st.Values) {
+				st.Values[ui] = v
+			}
+		}
+	}
+}
+
+// ConfigLog configures the table with one row per episode of the protocol
+func (hp *HelplessParams) ConfigLog(dt *etable.Table) {
+	sch := etable.Schema{
+		{"Phase", etensor.STRING, nil, nil},
+		{"Episode", etensor.INT64, nil, nil},
+		{"Latency", etensor.INT64, nil, nil},
+		{"Escaped", etensor.INT64, nil, nil},
+		{"Stress", etensor.FLOAT64, nil, nil},
+	}
+	dt.SetFromSchema(sch, 0)
+	dt.SetMetaData("name", "HelplessLog")
+	dt.SetMetaData("desc", "learned helplessness protocol: escape latency of each episode of the Yoked and Test phases")
+}
+
+// LogEpisode adds a row for an episode to the log
+func (hp *HelplessParams) LogEpisode(dt *etable.Table, phase string, ep, lat int, escaped bool, stress float32) {
+	row := dt.Rows
+	dt.SetNumRows(row + 1)
+	dt.SetCellString("Phase", row, phase)
+	dt.SetCellFloat("Episode", row, float64(ep))
+	dt.SetCellFloat("Latency", row, float64(lat))
+	esc := 0.0
+	if escaped {
+		esc = 1
+	}
+	dt.SetCellFloat("Escaped", row, esc)
+	dt.SetCellFloat("Stress", row, float64(stress))
+}
+
+// Summary sets the mean Latency and the learning Rate from the Test
+// episodes in the log
+func (hp *HelplessParams) Summary(dt *etable.Table) {
+	var n, sx, sy, sxx, sxy float64
+	for row := 0; row < dt.Rows; row++ {
+		if dt.CellString("Phase", row) != "Test" {
+			continue
+		}
+		x := dt.CellFloat("Episode", row)
+		y := dt.CellFloat("Latency", row)
+		n++
+		sx += x
+		sy += y
+		sxx += x * x
+		sxy += x * y
+	}
+	hp.Latency, hp.Rate = 0, 0
+	if n == 0 {
+		return
+	}
+	hp.Latency = float32(sy / n)
+	if den := n*sxx - sx*sx; den > 0 {
+		hp.Rate = float32((sx*sy - n*sxy) / den)
+	}
+}
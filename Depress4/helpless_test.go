@@ -0,0 +1,46 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/emer/etable/etable"
+)
+
+func TestHelplessSummary(t *testing.T) {
+	tests := []struct {
+		name    string
+		yoked   []int // latencies of the Yoked episodes, which are ignored
+		test    []int // latencies of the Test episodes
+		latency float32
+		rate    float32
+	}{
+		{"no episodes", nil, nil, 0, 0},
+		{"only yoked", []int{10, 10}, nil, 0, 0},
+		{"one test episode", nil, []int{6}, 6, 0},
+		{"constant latency", []int{10, 10}, []int{10, 10, 10, 10}, 10, 0},
+		{"learning", []int{10, 10, 10}, []int{10, 8, 6, 4}, 7, 2},
+		{"getting worse", nil, []int{2, 3, 4}, 3, -1},
+		{"noisy learning", nil, []int{9, 9, 5, 5}, 7, 1.6},
+	}
+	for _, tt := range tests {
+		hp := &HelplessParams{}
+		hp.Defaults()
+		dt := &etable.Table{}
+		hp.ConfigLog(dt)
+		for ep, lat := range tt.yoked {
+			hp.LogEpisode(dt, "Yoked", ep, lat, false, 0)
+		}
+		for ep, lat := range tt.test {
+			hp.LogEpisode(dt, "Test", ep, lat, lat < hp.MaxLat, 0)
+		}
+		hp.Summary(dt)
+		if math.Abs(float64(hp.Latency-tt.latency)) > 1e-5 || math.Abs(float64(hp.Rate-tt.rate)) > 1e-5 {
+			t.Errorf("%v: got Latency %v, Rate %v, want %v, %v", tt.name, hp.Latency, hp.Rate, tt.latency, tt.rate)
+		}
+	}
+}
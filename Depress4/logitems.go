@@ -53,6 +53,85 @@ func (ss *Sim) ConfigLogItems() {
 			etime.Scope(etime.AllModes, etime.Trial): func(ctx *elog.Context) {
 				ctx.SetStatString("TrialName")
 			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name: "Day",
+		Type: etensor.INT64,
+		Plot: elog.DFalse,
+		Write: elog.WriteMap{
+			etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+				ctx.SetStatInt("Day")
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name: "Tick",
+		Type: etensor.INT64,
+		Write: elog.WriteMap{
+			etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+				ctx.SetStatInt("Tick")
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name: "TickName",
+		Type: etensor.STRING,
+		Write: elog.WriteMap{
+			etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+				ctx.SetStatString("TickName")
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name:  "Hour",
+		Type:  etensor.FLOAT64,
+		Plot:  elog.DFalse,
+		Range: minmax.F64{Max: 24},
+		Write: elog.WriteMap{
+			etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+				ctx.SetStatFloat("Hour")
+			}}})
+	if nc := ss.WorldCircadian.Rows; nc > 0 {
+		ss.Logs.AddItem(&elog.Item{
+			Name:      "Circadian",
+			Type:      etensor.FLOAT64,
+			CellShape: []int{nc},
+			Write: elog.WriteMap{
+				etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+					tsr := ss.ValsTsr("Circadian")
+					tsr.SetShape([]int{nc}, nil, nil)
+					copy(tsr.Values, ss.WorldEnv.CircVals)
+					ctx.SetTensor(tsr)
+				}}})
+	}
+	ss.Logs.AddItem(&elog.Item{
+		Name: "ChosenBeh",
+		Type: etensor.INT64,
+		Plot: elog.DFalse,
+		Write: elog.WriteMap{
+			etime.Scope(etime.AllModes, etime.Trial): func(ctx *elog.Context) {
+				ctx.SetStatInt("ChosenBeh")
+			}, etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+				ctx.SetStatInt("ChosenBeh")
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name: "ChosenBehName",
+		Type: etensor.STRING,
+		Write: elog.WriteMap{
+			etime.Scope(etime.AllModes, etime.Trial): func(ctx *elog.Context) {
+				ctx.SetStatString("ChosenBehName")
+			}, etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+				ctx.SetStatString("ChosenBehName")
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name: "TargBeh",
+		Type: etensor.INT64,
+		Plot: elog.DFalse,
+		Write: elog.WriteMap{
+			etime.Scope(etime.AllModes, etime.Trial): func(ctx *elog.Context) {
+				ctx.SetStatInt("TargBeh")
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name: "RespBeh",
+		Type: etensor.INT64,
+		Plot: elog.DFalse,
+		Write: elog.WriteMap{
+			etime.Scope(etime.AllModes, etime.Trial): func(ctx *elog.Context) {
+				ctx.SetStatInt("RespBeh")
+			}}})
 	ss.Logs.AddItem(&elog.Item{
 		Name: "Cycle",
 		Type: etensor.INT64,
@@ -69,6 +148,15 @@ func (ss *Sim) ConfigLogItems() {
 			etime.Scope(etime.Train, etime.Run): func(ctx *elog.Context) {
 				ctx.SetStatInt("FirstZero")
 			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name:  "TrlsToCrit",
+		Type:  etensor.FLOAT64,
+		Plot:  elog.DFalse,
+		Range: minmax.F64{Min: -1},
+		Write: elog.WriteMap{
+			etime.Scope(etime.Train, etime.Run): func(ctx *elog.Context) {
+				ctx.SetStatInt("TrlsToCrit")
+			}}})
 	ss.Logs.AddItem(&elog.Item{
 		Name: "SSE",
 		Type: etensor.FLOAT64,
@@ -153,6 +241,197 @@ func (ss *Sim) ConfigLogItems() {
 				ix := ctx.LastNRows(etime.Train, etime.Epoch, 5) // cached
 				ctx.SetFloat64(agg.Mean(ix, ctx.Item.Name)[0])
 			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "Stress",
+		Type:   etensor.FLOAT64,
+		FixMax: elog.DTrue,
+		Range:  minmax.F64{Max: 1},
+		Write: elog.WriteMap{
+			etime.Scope(etime.AllModes, etime.Trial): func(ctx *elog.Context) {
+				ctx.SetStatFloat("Stress")
+			}, etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+				ctx.SetStatFloat("Stress")
+			}, etime.Scope(etime.AllModes, etime.Epoch): func(ctx *elog.Context) {
+				ctx.SetAgg(ctx.Mode, etime.Trial, agg.AggMean)
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "Reward",
+		Type:   etensor.FLOAT64,
+		FixMax: elog.DTrue,
+		Range:  minmax.F64{Max: 1},
+		Write: elog.WriteMap{
+			etime.Scope(etime.AllModes, etime.Trial): func(ctx *elog.Context) {
+				ctx.SetStatFloat("Reward")
+			}, etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+				ctx.SetStatFloat("Reward")
+			}, etime.Scope(etime.AllModes, etime.Epoch): func(ctx *elog.Context) {
+				ctx.SetAgg(ctx.Mode, etime.Trial, agg.AggMean)
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "TonicDA",
+		Type:   etensor.FLOAT64,
+		FixMax: elog.DTrue,
+		Range:  minmax.F64{Max: 1},
+		Write: elog.WriteMap{
+			etime.Scope(etime.AllModes, etime.Trial): func(ctx *elog.Context) {
+				ctx.SetStatFloat("TonicDA")
+			}, etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+				ctx.SetStatFloat("TonicDA")
+			}, etime.Scope(etime.AllModes, etime.Epoch): func(ctx *elog.Context) {
+				ctx.SetAgg(ctx.Mode, etime.Trial, agg.AggMean)
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "RPE",
+		Type:   etensor.FLOAT64,
+		Plot:   elog.DTrue,
+		FixMin: elog.DTrue,
+		FixMax: elog.DTrue,
+		Range:  minmax.F64{Min: -1, Max: 1},
+		Write: elog.WriteMap{
+			etime.Scope(etime.AllModes, etime.Trial): func(ctx *elog.Context) {
+				ctx.SetStatFloat("RPE")
+			}, etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+				ctx.SetStatFloat("RPE")
+			}, etime.Scope(etime.AllModes, etime.Epoch): func(ctx *elog.Context) {
+				ctx.SetAgg(ctx.Mode, etime.Trial, agg.AggMean)
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "LrateMod",
+		Type:   etensor.FLOAT64,
+		FixMin: elog.DTrue,
+		FixMax: elog.DTrue,
+		Range:  minmax.F64{Min: 0, Max: 1},
+		Write: elog.WriteMap{
+			etime.Scope(etime.AllModes, etime.Trial): func(ctx *elog.Context) {
+				ctx.SetStatFloat("LrateMod")
+			}, etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+				ctx.SetStatFloat("LrateMod")
+			}, etime.Scope(etime.AllModes, etime.Epoch): func(ctx *elog.Context) {
+				ctx.SetAgg(ctx.Mode, etime.Trial, agg.AggMean)
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "DrugResp",
+		Type:   etensor.FLOAT64,
+		FixMin: elog.DTrue,
+		FixMax: elog.DTrue,
+		Range:  minmax.F64{Min: 0, Max: 1},
+		Write: elog.WriteMap{
+			etime.Scope(etime.AllModes, etime.Trial): func(ctx *elog.Context) {
+				ctx.SetStatFloat("DrugResp")
+			}, etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+				ctx.SetStatFloat("DrugResp")
+			}, etime.Scope(etime.AllModes, etime.Epoch): func(ctx *elog.Context) {
+				ctx.SetAgg(ctx.Mode, etime.Trial, agg.AggMean)
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name: "Phase",
+		Type: etensor.STRING,
+		Write: elog.WriteMap{
+			etime.Scopes([]etime.Modes{etime.AllModes}, []etime.Times{etime.Run, etime.Epoch, etime.Trial}): func(ctx *elog.Context) {
+				ctx.SetStatString("Phase")
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "ApproachAct",
+		Type:   etensor.FLOAT64,
+		Plot:   elog.DTrue,
+		FixMax: elog.DTrue,
+		Range:  minmax.F64{Max: 1},
+		Write: elog.WriteMap{
+			etime.Scope(etime.AllModes, etime.Trial): func(ctx *elog.Context) {
+				ctx.SetStatFloat("ApproachAct")
+			}, etime.Scope(etime.AllModes, etime.Epoch): func(ctx *elog.Context) {
+				ctx.SetAgg(ctx.Mode, etime.Trial, agg.AggMean)
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "AvoidAct",
+		Type:   etensor.FLOAT64,
+		Plot:   elog.DTrue,
+		FixMax: elog.DTrue,
+		Range:  minmax.F64{Max: 1},
+		Write: elog.WriteMap{
+			etime.Scope(etime.AllModes, etime.Trial): func(ctx *elog.Context) {
+				ctx.SetStatFloat("AvoidAct")
+			}, etime.Scope(etime.AllModes, etime.Epoch): func(ctx *elog.Context) {
+				ctx.SetAgg(ctx.Mode, etime.Trial, agg.AggMean)
+			}}})
+
+	// Approach - avoid motive stats
+	for _, nm := range []string{"ApproachTot", "AvoidTot", "ApproachMax", "AvoidMax", "AppAvBalance", "AppAvConflict"} {
+		stnm := nm
+		ss.Logs.AddItem(&elog.Item{
+			Name: stnm,
+			Type: etensor.FLOAT64,
+			Plot: stnm == "AppAvBalance" || stnm == "AppAvConflict",
+			Write: elog.WriteMap{
+				etime.Scope(etime.AllModes, etime.Trial): func(ctx *elog.Context) {
+					ctx.SetStatFloat(stnm)
+				}, etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+					ctx.SetStatFloat(stnm)
+				}, etime.Scope(etime.AllModes, etime.Epoch): func(ctx *elog.Context) {
+					ctx.SetAgg(ctx.Mode, etime.Trial, agg.AggMean)
+				}, etime.Scope(etime.AllModes, etime.Run): func(ctx *elog.Context) {
+					ix := ctx.LastNRows(ctx.Mode, etime.Epoch, 5) // cached
+					ctx.SetFloat64(agg.Mean(ix, ctx.Item.Name)[0])
+				}}})
+	}
+	ss.Logs.AddItem(&elog.Item{
+		Name: "WinMotive",
+		Type: etensor.INT64,
+		Plot: elog.DFalse,
+		Write: elog.WriteMap{
+			etime.Scope(etime.AllModes, etime.Trial): func(ctx *elog.Context) {
+				ctx.SetStatInt("WinMotive")
+			}, etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+				ctx.SetStatInt("WinMotive")
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name: "WinMotiveName",
+		Type: etensor.STRING,
+		Write: elog.WriteMap{
+			etime.Scope(etime.AllModes, etime.Trial): func(ctx *elog.Context) {
+				ctx.SetStatString("WinMotiveName")
+			}, etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+				ctx.SetStatString("WinMotiveName")
+			}, etime.Scope(etime.AllModes, etime.Epoch): func(ctx *elog.Context) {
+				ctx.SetString(ModalString(ctx.Logs.Table(ctx.Mode, etime.Trial), ctx.Item.Name))
+			}, etime.Scope(etime.AllModes, etime.Run): func(ctx *elog.Context) {
+				ctx.SetString(ModalString(ctx.Logs.Table(ctx.Mode, etime.Epoch), ctx.Item.Name))
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "RT",
+		Type:   etensor.FLOAT64,
+		Plot:   elog.DTrue,
+		FixMin: elog.DTrue,
+		Range:  minmax.F64{Min: 0},
+		Write: elog.WriteMap{
+			etime.Scope(etime.Test, etime.Trial): func(ctx *elog.Context) {
+				ctx.SetStatInt("RT")
+			}, etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+				ctx.SetStatInt("RT")
+			}, etime.Scope(etime.Test, etime.Epoch): func(ctx *elog.Context) {
+				ctx.SetAgg(ctx.Mode, etime.Trial, agg.AggMean)
+			}}})
+
+	// Symptom scores, averaged over the epoch, or the current day of the World
+	for _, nm := range SymptomNames {
+		stnm := nm
+		ss.Logs.AddItem(&elog.Item{
+			Name:   stnm,
+			Type:   etensor.FLOAT64,
+			Plot:   stnm == "Symptoms",
+			FixMin: elog.DTrue,
+			FixMax: elog.DTrue,
+			Range:  minmax.F64{Min: 0, Max: 1},
+			Write: elog.WriteMap{
+				etime.Scope(etime.AllModes, etime.Epoch): func(ctx *elog.Context) {
+					ctx.SetStatFloat(stnm)
+				}, etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+					ctx.SetStatFloat(stnm)
+				}, etime.Scope(etime.AllModes, etime.Run): func(ctx *elog.Context) {
+					ix := ctx.LastNRows(ctx.Mode, etime.Epoch, 5) // cached
+					ctx.SetFloat64(agg.Mean(ix, ctx.Item.Name)[0])
+				}}})
+	}
 	ss.Logs.AddItem(&elog.Item{
 		Name: "PerTrlMSec",
 		Type: etensor.FLOAT64,
@@ -222,11 +501,16 @@ func (ss *Sim) ConfigLogItems() {
 				}}})
 	}
 
-	// input / output layer activity patterns during testing
+	// input / output layer activity patterns during testing -- one column per
+	// unit, named by its label (e.g., Behavior_Eat), for layers with UnitLabels
 	layers = ss.Net.LayersByClass("Input", "Target")
 	for _, lnm := range layers {
 		clnm := lnm
 		cly := ss.Net.LayerByName(clnm)
+		if len(ss.UnitLabels[clnm]) > 0 {
+			ss.ConfigUnitLogItems(clnm)
+			continue
+		}
 		ss.Logs.AddItem(&elog.Item{
 			Name:      clnm + "_Act",
 			Type:      etensor.FLOAT64,
@@ -234,7 +518,7 @@ func (ss *Sim) ConfigLogItems() {
 			FixMax:    elog.DTrue,
 			Range:     minmax.F64{Max: 1},
 			Write: elog.WriteMap{
-				etime.Scope(etime.Test, etime.Trial): func(ctx *elog.Context) {
+				etime.Scopes([]etime.Modes{etime.Test}, []etime.Times{etime.Trial, etime.Tick}): func(ctx *elog.Context) {
 					ctx.SetLayerTensor(clnm, "Act")
 				}}})
 		if cly.Type() == emer.Target {
@@ -245,12 +529,24 @@ func (ss *Sim) ConfigLogItems() {
 				FixMax:    elog.DTrue,
 				Range:     minmax.F64{Max: 1},
 				Write: elog.WriteMap{
-					etime.Scope(etime.Test, etime.Trial): func(ctx *elog.Context) {
+					etime.Scopes([]etime.Modes{etime.Test}, []etime.Times{etime.Trial, etime.Tick}): func(ctx *elog.Context) {
 						ctx.SetLayerTensor(clnm, "ActM")
 					}}})
 		}
 	}
 
+	// VTA dopamine activity over time in the closed-loop World
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "VTA_ActMAvg",
+		Type:   etensor.FLOAT64,
+		FixMax: elog.DTrue,
+		Range:  minmax.F64{Max: 1},
+		Write: elog.WriteMap{
+			etime.Scope(etime.Test, etime.Tick): func(ctx *elog.Context) {
+				ly := ctx.Layer("VTA").(leabra.LeabraLayer).AsLeabra()
+				ctx.SetFloat32(ly.Pools[0].ActM.Avg)
+			}}})
+
 	// hidden activities for PCA analysis, and PCA results
 	layers = ss.Net.LayersByClass("Hidden")
 	for _, lnm := range layers {
@@ -300,3 +596,36 @@ func (ss *Sim) ConfigLogItems() {
 				}}})
 	}
 }
+
+// ConfigUnitLogItems adds a column for the activity of each unit of the given
+// layer during testing, named by its label (e.g., Behavior_Eat), and for a
+// Target layer, a column for its minus phase activity (e.g., Behavior_Eat_M)
+func (ss *Sim) ConfigUnitLogItems(lnm string) {
+	cly := ss.Net.LayerByName(lnm)
+	for ui := range ss.UnitLabels[lnm] {
+		cui := ui
+		nm := ss.UnitLabels.UnitName(lnm, ui)
+		ss.Logs.AddItem(&elog.Item{
+			Name:   nm,
+			Type:   etensor.FLOAT64,
+			FixMax: elog.DTrue,
+			Range:  minmax.F64{Max: 1},
+			Write: elog.WriteMap{
+				etime.Scopes([]etime.Modes{etime.Test}, []etime.Times{etime.Trial, etime.Tick}): func(ctx *elog.Context) {
+					ly := ctx.Layer(lnm).(leabra.LeabraLayer).AsLeabra()
+					ctx.SetFloat32(ly.Neurons[cui].Act)
+				}}})
+		if cly.Type() == emer.Target {
+			ss.Logs.AddItem(&elog.Item{
+				Name:   nm + "_M",
+				Type:   etensor.FLOAT64,
+				FixMax: elog.DTrue,
+				Range:  minmax.F64{Max: 1},
+				Write: elog.WriteMap{
+					etime.Scopes([]etime.Modes{etime.Test}, []etime.Times{etime.Trial, etime.Tick}): func(ctx *elog.Context) {
+						ly := ctx.Layer(lnm).(leabra.LeabraLayer).AsLeabra()
+						ctx.SetFloat32(ly.Neurons[cui].ActM)
+					}}})
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/goki/ki/kit"
+)
+
+// SelectParams are the parameters for selecting a single Behavior from the
+// settled activity of the Behavior layer.  The stochastic policies draw from
+// their own random source, so selection does not change the sequence of the
+// global one used for training, e.g., the order of the trials.
+type SelectParams struct {
+	Policy  SelPolicies `desc:"policy for selecting the Behavior"`
+	Temp    float32     `viewif:"Policy=SoftMax" def:"0.1" desc:"temperature for SoftMax -- lower values select the most active Behavior more often -- must be > 0"`
+	Epsilon float32     `viewif:"Policy=EpsGreedy" def:"0.1" desc:"probability of selecting a random Behavior for EpsGreedy"`
+	MinAct  float32     `def:"0.1" desc:"minimum activity of the most active Behavior for any Behavior to be selected -- otherwise none is (-1)"`
+	Rand    *rand.Rand  `view:"-" desc:"random source of the stochastic policies"`
+}
+
+func (sp *SelectParams) Defaults() {
+	sp.Policy = ArgMax
+	sp.Temp = 0.1
+	sp.Epsilon = 0.1
+	sp.MinAct = 0.1
+	sp.Seed(1)
+}
+
+// Seed sets the random source to given seed, e.g., that of the run
+func (sp *SelectParams) Seed(seed int64) {
+	sp.Rand = rand.New(rand.NewSource(seed))
+}
+
+// Validate checks that the parameters of the Policy are in range
+func (sp *SelectParams) Validate() error {
+	switch {
+	case sp.Policy == SoftMax && sp.Temp <= 0:
+		return fmt.Errorf("SelectParams: SoftMax Temp must be > 0, is: %v", sp.Temp)
+	case sp.Policy == EpsGreedy && (sp.Epsilon < 0 || sp.Epsilon > 1):
+		return fmt.Errorf("SelectParams: EpsGreedy Epsilon must be between 0 and 1, is: %v", sp.Epsilon)
+	}
+	return nil
+}
+
+// Select returns the index of the Behavior selected from the given activities
+// according to the Policy, or -1 if the most active is below MinAct
+func (sp *SelectParams) Select(acts []float32) int {
+	maxi := -1
+	var max float32
+	for i, a := range acts {
+		if maxi < 0 || a > max {
+			maxi = i
+			max = a
+		}
+	}
+	if maxi < 0 || max < sp.MinAct {
+		return -1
+	}
+	switch sp.Policy {
+	case SoftMax:
+		if sp.Temp <= 0 { // limit of zero temperature
+			return maxi
+		}
+		ps := make([]float32, len(acts))
+		var sum float32
+		for i, a := range acts {
+			ps[i] = float32(math.Exp(float64((a - max) / sp.Temp))) // subtract max for stability
+			sum += ps[i]
+		}
+		pv := sp.Rand.Float32() * sum
+		for i, p := range ps {
+			pv -= p
+			if pv < 0 {
+				return i
+			}
+		}
+		return len(ps) - 1
+	case EpsGreedy:
+		if sp.Rand.Float32() < sp.Epsilon {
+			return sp.Rand.Intn(len(acts))
+		}
+	}
+	return maxi
+}
+
+// SelPolicies are the policies for selecting a single Behavior
+type SelPolicies int32
+
+//go:generate stringer -type=SelPolicies
+
+var KiT_SelPolicies = kit.Enums.AddEnum(SelPoliciesN, kit.NotBitFlag, nil)
+
+func (ev SelPolicies) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *SelPolicies) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// The Behavior selection policies
+const (
+	// ArgMax selects the most active Behavior
+	ArgMax SelPolicies = iota
+
+	// SoftMax selects each Behavior with probability proportional to exp(act / Temp)
+	SoftMax
+
+	// EpsGreedy selects the most active Behavior, except with probability Epsilon
+	// it selects one at random
+	EpsGreedy
+
+	SelPoliciesN
+)
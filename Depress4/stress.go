@@ -0,0 +1,60 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+// StressParams accumulate chronic Stress from aversive experience on each training
+// trial and World time step (not when testing), with slow decay, and set the DyDA
+// (dynorphin) input from it, which in turn down-regulates Approach and VTA
+// dopamine through the DyDAToApproach and DyDAToVTA inhibitory projections.
+type StressParams struct {
+	On          bool    `desc:"if true, DyDA input is set from the Stress level on every trial, instead of from the input patterns -- on in the Stress ParamSet"`
+	AvoidGain   float32 `viewif:"On" def:"0.1" desc:"increase in Stress per trial, per unit of average Avoidance layer activity"`
+	ThreatGain  float32 `viewif:"On" def:"0.1" desc:"increase in Stress per trial, per unit of activity of the ThreatUnits of EnviroFeatures"`
+	NeedGain    float32 `viewif:"On" def:"0.05" desc:"increase in Stress per trial, per unit of unmet need -- InteroState activity above NeedThr"`
+	NeedThr     float32 `viewif:"On" def:"0.8" desc:"InteroState activity above which a need is unmet"`
+	Decay       float32 `viewif:"On" def:"0.02" desc:"proportion of Stress that decays per trial -- slow recovery"`
+	DyDAGain    float32 `viewif:"On" def:"1" desc:"gain on Stress for the DyDA input"`
+	ThreatUnits []int   `viewif:"On" desc:"indexes of the EnviroFeatures units that are aversive (e.g., SocSit, Dngr)"`
+	Stress      float32 `inactive:"+" desc:"current level of chronic Stress (0-1)"`
+}
+
+func (sp *StressParams) Defaults() {
+	sp.On = false
+	sp.AvoidGain = 0.1
+	sp.ThreatGain = 0.1
+	sp.NeedGain = 0.05
+	sp.NeedThr = 0.8
+	sp.Decay = 0.02
+	sp.DyDAGain = 1
+	sp.ThreatUnits = []int{5, 6}
+}
+
+// Init resets the Stress level
+func (sp *StressParams) Init() {
+	sp.Stress = 0
+}
+
+// Update updates the Stress level from the Avoidance activity, the threat
+// EnviroFeatures activities and the InteroState activities on this trial
+func (sp *StressParams) Update(avoid float32, enviro, intero []float32) {
+	var threat, need float32
+	for _, ui := range sp.ThreatUnits {
+		if ui < len(enviro) {
+			threat += enviro[ui]
+		}
+	}
+	for _, v := range intero {
+		if v > sp.NeedThr {
+			need += v - sp.NeedThr
+		}
+	}
+	sp.Stress += sp.AvoidGain*avoid + sp.ThreatGain*threat + sp.NeedGain*need - sp.Decay*sp.Stress
+	sp.Stress = ClipUnit(sp.Stress)
+}
+
+// DyDA returns the DyDA input value for the current Stress level
+func (sp *StressParams) DyDA() float32 {
+	return ClipUnit(sp.DyDAGain * sp.Stress)
+}
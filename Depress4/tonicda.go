@@ -0,0 +1,57 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+// TonicDAParams slowly adapt the tonic drive of the VTA (its Act.Noise.Mean
+// baseline) toward a set-point that depends on the recent history of Reward and
+// on DyDA: a lack of reward and chronic stress gradually lower the tonic dopamine
+// drive (anhedonia), and renewed reward gradually restores it.
+type TonicDAParams struct {
+	On       bool    `desc:"if true, the tonic VTA drive adapts on every training trial and World time step, instead of staying at the Base set by params -- on in the TonicDA ParamSet"`
+	Tau      float32 `viewif:"On" def:"200" min:"1" desc:"time constant in trials for the adaptation of the tonic drive, and the running average of Reward -- slow"`
+	Min      float32 `viewif:"On" def:"0.1" desc:"minimum tonic VTA drive"`
+	Max      float32 `viewif:"On" def:"0.6" desc:"maximum tonic VTA drive"`
+	RewGain  float32 `viewif:"On" def:"0.5" desc:"gain on the difference of the average Reward from RewRef, for the set-point"`
+	RewRef   float32 `viewif:"On" def:"0.2" desc:"average Reward at which the set-point is the Base drive"`
+	DyDAGain float32 `viewif:"On" def:"0.3" desc:"reduction in the set-point per unit of DyDA"`
+	Base     float32 `inactive:"+" desc:"baseline tonic VTA drive, from the VTA Act.Noise.Mean set by params"`
+	RewAvg   float32 `inactive:"+" desc:"running average of Reward"`
+	Drive    float32 `inactive:"+" desc:"current tonic VTA drive -- sets the VTA Act.Noise.Mean"`
+}
+
+func (tp *TonicDAParams) Defaults() {
+	tp.On = false
+	tp.Tau = 200
+	tp.Min = 0.1
+	tp.Max = 0.6
+	tp.RewGain = 0.5
+	tp.RewRef = 0.2
+	tp.DyDAGain = 0.3
+}
+
+// Init resets the Drive to the Base and the average Reward to RewRef
+func (tp *TonicDAParams) Init() {
+	tp.RewAvg = tp.RewRef
+	tp.Drive = tp.Base
+}
+
+// SetPoint returns the current target tonic drive, for given DyDA
+func (tp *TonicDAParams) SetPoint(dyda float32) float32 {
+	return tp.Base + tp.RewGain*(tp.RewAvg-tp.RewRef) - tp.DyDAGain*dyda
+}
+
+// Update updates the average Reward and moves the Drive toward the SetPoint,
+// given the Reward and DyDA on this trial
+func (tp *TonicDAParams) Update(rew, dyda float32) {
+	dt := 1 / tp.Tau
+	tp.RewAvg += dt * (rew - tp.RewAvg)
+	tp.Drive += dt * (tp.SetPoint(dyda) - tp.Drive)
+	switch {
+	case tp.Drive < tp.Min:
+		tp.Drive = tp.Min
+	case tp.Drive > tp.Max:
+		tp.Drive = tp.Max
+	}
+}
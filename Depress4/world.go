@@ -0,0 +1,402 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/emergent/env"
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// WorldEnv is a closed-loop environment that holds the current State of the World,
+// both external (EnviroFeatures) and internal (InteroState).  Each time step the
+// Behavior chosen by the network (passed in via Action) and the exogenous changes
+// in the WorldChanges table are added to the State, which is then presented to
+// the network on the next step.  All State values are limited to the 0-1 range.
+//
+// The Effects table is the rule table for the consequences of each Behavior, with
+// one row per Behavior unit, and these columns:
+//   - Name: name of the Behavior
+//   - EnviroFeatures, InteroState: change in each unit of the State layers
+//   - Delay: number of time steps before the effects are applied (optional)
+//   - Cost: value of the Cost layer unit for this Behavior (optional)
+//
+// If a Dynamics table is set, the effects of Behavior on the DynLay (InteroState)
+// are scheduled after a further Delay specific to each unit, with a size given by its
+// Decr, and each unit also changes by its Incr with the passage of time.
+//
+// If a Circadian table is set, the Incr is modulated by the time of day on the
+// simulated Clock, and features can be gated by time of day (see circadian.go).
+type WorldEnv struct {
+	Nm        string                      `desc:"name of this environment"`
+	Dsc       string                      `desc:"description of this environment"`
+	World     *etable.Table               `desc:"initial State of the World -- the first row has the starting EnviroFeatures and InteroState, along with any other input layers (MBApp, MBAv, Cost, DyDA) which are held constant"`
+	Changes   *etable.Table               `desc:"exogenous Changes in the World -- row n is added to the State at time step n, so it must have at least one row.  A feature enters with a positive value and leaves with -1.  The first row is typically blank."`
+	Effects   *etable.Table               `desc:"rule table for the effects of each Behavior on the World -- row b is added to the State on the step after Behavior b has been chosen, plus its Delay.  For the DynLay, if Dynamics is set, values are weights on the Decr for each unit instead.  The Cost column sets the Cost layer."`
+	Dynamics  *etable.Table               `desc:"optional dynamics of the DynLay -- one row per unit, with Delay (additional time steps before the effect of Behavior is applied), Decr (change per relevant Behavior) and Incr (change per time step, regardless of Behavior)"`
+	DynLay    string                      `desc:"name of the layer whose units have the Dynamics -- defaults to InteroState"`
+	StateLays []string                    `desc:"names of the layers whose State is changed by Behavior and by the Changes table"`
+	BehLay    string                      `desc:"name of the Behavior layer -- one row in Effects per unit -- defaults to Behavior"`
+	CostLay   string                      `desc:"name of the Cost layer, set from the Cost column of Effects -- defaults to Cost"`
+	States    map[string]*etensor.Float32 `desc:"current State of the World for each input layer"`
+	Behavior  env.CurPrvInt               `desc:"index of the Behavior chosen on the current step -- -1 if none has been chosen yet"`
+	Run       env.Ctr                     `view:"inline" desc:"current run of model as provided during Init"`
+	Epoch     env.Ctr                     `view:"inline" desc:"number of times through the entire Changes table"`
+	Tick      env.Ctr                     `view:"inline" desc:"current time step -- row in the Changes table"`
+	TickName  env.CurPrvString            `desc:"if Changes has a Name column, this is the contents of that"`
+	NameCol   string                      `desc:"name of the Name column -- defaults to 'Name'"`
+	Pending   []WorldDelta                `view:"-" desc:"effects of Behavior that have been scheduled but not yet applied"`
+	Circadian *etable.Table               `desc:"optional time-of-day modulation of the Incr of DynLay units, and gating of State features -- see CircadianRow"`
+	Clock     WorldClock                  `view:"inline" desc:"simulated clock time of day"`
+	CircVals  []float32                   `desc:"current value of each row of the Circadian table: multiplier on the Incr, or 1 = open, 0 = closed for gating"`
+	Obs       map[string]*etensor.Float32 `view:"-" desc:"observed State of the World presented to the network -- State with gating applied"`
+}
+
+// WorldDelta is a scheduled change in the State of one unit in the World
+type WorldDelta struct {
+	Lay   string  `desc:"name of the State layer"`
+	Idx   int     `desc:"index of the unit within the layer"`
+	Val   float32 `desc:"amount to add to the State"`
+	Delay int     `desc:"number of time steps remaining before the change is applied"`
+}
+
+func (ev *WorldEnv) Name() string { return ev.Nm }
+func (ev *WorldEnv) Desc() string { return ev.Dsc }
+
+func (ev *WorldEnv) Validate() error {
+	ev.Defaults()
+	if ev.World == nil || ev.World.Rows == 0 {
+		return fmt.Errorf("WorldEnv: %v has no World table set", ev.Nm)
+	}
+	if ev.Changes == nil || ev.Changes.Rows == 0 { // one row per time step of the day
+		return fmt.Errorf("WorldEnv: %v has no Changes table set", ev.Nm)
+	}
+	for _, lnm := range ev.StateLays {
+		if ev.World.ColByName(lnm) == nil {
+			return fmt.Errorf("WorldEnv: %v World table has no column for State layer: %v", ev.Nm, lnm)
+		}
+		if ev.Changes.ColByName(lnm) == nil {
+			return fmt.Errorf("WorldEnv: %v Changes table has no column for State layer: %v", ev.Nm, lnm)
+		}
+	}
+	if ev.Effects != nil {
+		for _, cnm := range []string{"Delay", "Cost"} {
+			if cl := ev.Effects.ColByName(cnm); cl != nil && cl.NumDims() != 1 {
+				return fmt.Errorf("WorldEnv: %v Effects table %v column must be a single value per Behavior", ev.Nm, cnm)
+			}
+		}
+	}
+	if err := ev.ValidateCircadian(); err != nil {
+		return err
+	}
+	if ev.Dynamics != nil {
+		for _, cnm := range []string{"Delay", "Decr", "Incr"} {
+			if ev.Dynamics.ColByName(cnm) == nil {
+				return fmt.Errorf("WorldEnv: %v Dynamics table has no %v column", ev.Nm, cnm)
+			}
+		}
+		if cl := ev.World.ColByName(ev.DynLay); cl != nil && cl.Len()/cl.Dim(0) != ev.Dynamics.Rows {
+			return fmt.Errorf("WorldEnv: %v Dynamics table has %v rows but %v has %v units", ev.Nm, ev.Dynamics.Rows, ev.DynLay, cl.Len()/cl.Dim(0))
+		}
+	}
+	return nil
+}
+
+// ValidateNet checks that the World, Changes and Effects tables match the
+// shapes of the corresponding layers in given network: each layer column must
+// have one value per unit, and Effects must have one row per Behavior unit.
+func (ev *WorldEnv) ValidateNet(net emer.Network) error {
+	ev.Defaults()
+	for _, dt := range []*etable.Table{ev.World, ev.Changes, ev.Effects} {
+		if dt == nil {
+			continue
+		}
+		for ci, cl := range dt.Cols {
+			if cl.NumDims() == 1 || cl.DataType() == etensor.STRING {
+				continue
+			}
+			ly, err := net.LayerByNameTry(dt.ColNames[ci])
+			if err != nil {
+				continue
+			}
+			if cn, ln := cl.Len()/cl.Dim(0), ly.Shape().Len(); cn != ln {
+				return fmt.Errorf("WorldEnv: %v table %v column has %v values but layer has %v units", ev.Nm, dt.ColNames[ci], cn, ln)
+			}
+		}
+	}
+	if ev.Effects == nil {
+		return nil
+	}
+	for _, lnm := range []string{ev.BehLay, ev.CostLay} {
+		if lnm == ev.CostLay && ev.Effects.ColByName("Cost") == nil {
+			continue
+		}
+		ly, err := net.LayerByNameTry(lnm)
+		if err != nil {
+			return fmt.Errorf("WorldEnv: %v %v", ev.Nm, err)
+		}
+		if ln := ly.Shape().Len(); ln != ev.Effects.Rows {
+			return fmt.Errorf("WorldEnv: %v Effects table has %v rows but layer %v has %v units", ev.Nm, ev.Effects.Rows, lnm, ln)
+		}
+	}
+	return nil
+}
+
+// Defaults sets default names for any that have not been set
+func (ev *WorldEnv) Defaults() {
+	if ev.NameCol == "" {
+		ev.NameCol = "Name"
+	}
+	if ev.StateLays == nil {
+		ev.StateLays = []string{"EnviroFeatures", "InteroState"}
+	}
+	if ev.DynLay == "" {
+		ev.DynLay = "InteroState"
+	}
+	if ev.BehLay == "" {
+		ev.BehLay = "Behavior"
+	}
+	if ev.CostLay == "" {
+		ev.CostLay = "Cost"
+	}
+}
+
+// Init sets the State of the World back to the first row of the World table,
+// with the Cost layer set from the Cost column of the Effects table
+func (ev *WorldEnv) Init(run int) {
+	ev.Defaults()
+	ev.Run.Scale = env.Run
+	ev.Epoch.Scale = env.Epoch
+	ev.Tick.Scale = env.Tick
+	ev.Run.Init()
+	ev.Epoch.Init()
+	ev.Tick.Init()
+	ev.Run.Cur = run
+	ev.Tick.Max = 0
+	if ev.Changes != nil {
+		ev.Tick.Max = ev.Changes.Rows
+	}
+	ev.Tick.Cur = -1 // init state -- key so that first Step() = 0
+	ev.Behavior.Cur = -1
+	ev.Behavior.Prv = -1
+	ev.Pending = nil
+
+	ev.States = make(map[string]*etensor.Float32)
+	for ci, cl := range ev.World.Cols {
+		if cl.NumDims() == 1 || cl.DataType() == etensor.STRING {
+			continue
+		}
+		st := &etensor.Float32{}
+		cell := cl.SubSpace([]int{0})
+		st.CopyShapeFrom(cell)
+		st.CopyFrom(cell)
+		ev.States[ev.World.ColNames[ci]] = st
+	}
+	if ev.Effects != nil && ev.Effects.ColByName("Cost") != nil {
+		if st, has := ev.States[ev.CostLay]; has {
+			for i := range st.Values {
+				if i >= ev.Effects.Rows {
+					break
+				}
+				st.Values[i] = float32(ev.Effects.CellFloat("Cost", i))
+			}
+		}
+	}
+	ev.Obs = make(map[string]*etensor.Float32)
+	for lnm, st := range ev.States {
+		ob := &etensor.Float32{}
+		ob.CopyShapeFrom(st)
+		ob.CopyFrom(st)
+		ev.Obs[lnm] = ob
+	}
+	ev.InitCircadian()
+}
+
+// Step updates the State of the World: the effects of the Behavior chosen on
+// the last step are added (or scheduled) first, then any Pending effects that
+// are due, the Incr for the passage of time, and the exogenous Changes for the new step.
+func (ev *WorldEnv) Step() bool {
+	ev.Epoch.Same() // good idea to just reset all non-inner-most counters at start
+
+	if ev.Tick.Cur >= 0 {
+		if ev.Behavior.Cur >= 0 {
+			ev.AddEffects(ev.Behavior.Cur)
+		}
+		ev.ApplyPending()
+		ev.AddIncr()
+	}
+	if ev.Tick.Incr() { // if true, hit max, reset to 0
+		ev.Epoch.Incr()
+	}
+	ev.AddRow(ev.Changes, ev.Tick.Cur)
+	ev.SetTickName()
+	ev.UpdateCircadian()
+	ev.UpdateObs()
+	return true
+}
+
+// AddRow adds the values in given row of table to the State of each of the
+// StateLays, and limits the result to the 0-1 range.
+func (ev *WorldEnv) AddRow(dt *etable.Table, row int) {
+	if dt == nil || row < 0 || row >= dt.Rows {
+		return
+	}
+	for _, lnm := range ev.StateLays {
+		st, has := ev.States[lnm]
+		if !has {
+			continue
+		}
+		dtsr := dt.CellTensor(lnm, row)
+		if dtsr == nil {
+			continue
+		}
+		for i := range st.Values {
+			if i >= dtsr.Len() {
+				break
+			}
+			st.Values[i] = ClipUnit(st.Values[i] + float32(dtsr.FloatVal1D(i)))
+		}
+	}
+}
+
+// AddEffects schedules the Effects of given Behavior as Pending changes, after
+// the Delay for the Behavior -- effects on the DynLay are weighted by the Decr and
+// further delayed by the Delay for each unit, if the Dynamics table is set.
+func (ev *WorldEnv) AddEffects(beh int) {
+	if ev.Effects == nil || beh >= ev.Effects.Rows {
+		return
+	}
+	bdly := 0
+	if ev.Effects.ColByName("Delay") != nil {
+		bdly = int(ev.Effects.CellFloat("Delay", beh))
+	}
+	for _, lnm := range ev.StateLays {
+		st, has := ev.States[lnm]
+		if !has {
+			continue
+		}
+		etsr := ev.Effects.CellTensor(lnm, beh)
+		if etsr == nil {
+			continue
+		}
+		dyn := ev.Dynamics != nil && lnm == ev.DynLay
+		for i := range st.Values {
+			if i >= etsr.Len() {
+				break
+			}
+			ef := float32(etsr.FloatVal1D(i))
+			if ef == 0 {
+				continue
+			}
+			dly := bdly
+			if dyn {
+				if i >= ev.Dynamics.Rows {
+					break
+				}
+				ef *= float32(ev.Dynamics.CellFloat("Decr", i))
+				dly += int(ev.Dynamics.CellFloat("Delay", i))
+			}
+			ev.Pending = append(ev.Pending, WorldDelta{Lay: lnm, Idx: i, Val: ef, Delay: dly})
+		}
+	}
+}
+
+// ApplyPending applies the Pending changes whose Delay has run out, and counts
+// down the Delay for the rest
+func (ev *WorldEnv) ApplyPending() {
+	keep := ev.Pending[:0]
+	for _, pd := range ev.Pending {
+		if pd.Delay > 0 {
+			pd.Delay--
+			keep = append(keep, pd)
+			continue
+		}
+		if st, has := ev.States[pd.Lay]; has && pd.Idx < len(st.Values) {
+			st.Values[pd.Idx] = ClipUnit(st.Values[pd.Idx] + pd.Val)
+		}
+	}
+	ev.Pending = keep
+}
+
+// AddIncr adds the Incr for each unit of the DynLay, for the passage of one time step,
+// modulated by the time of day if Circadian is set
+func (ev *WorldEnv) AddIncr() {
+	if ev.Dynamics == nil {
+		return
+	}
+	st, has := ev.States[ev.DynLay]
+	if !has {
+		return
+	}
+	for i := range st.Values {
+		if i >= ev.Dynamics.Rows {
+			break
+		}
+		st.Values[i] = ClipUnit(st.Values[i] + ev.IncrMod(i)*float32(ev.Dynamics.CellFloat("Incr", i)))
+	}
+}
+
+// SetTickName sets the TickName from the Name column of the Changes table
+func (ev *WorldEnv) SetTickName() {
+	if ev.Changes == nil {
+		return
+	}
+	if nms := ev.Changes.ColByName(ev.NameCol); nms != nil {
+		if ev.Tick.Cur < nms.Len() {
+			ev.TickName.Set(nms.StringVal1D(ev.Tick.Cur))
+		}
+	}
+}
+
+func (ev *WorldEnv) Counter(scale env.TimeScales) (cur, prv int, chg bool) {
+	switch scale {
+	case env.Run:
+		return ev.Run.Query()
+	case env.Epoch:
+		return ev.Epoch.Query()
+	case env.Tick:
+		return ev.Tick.Query()
+	}
+	return -1, -1, false
+}
+
+// State returns the current observed State of the World for given layer -- nil for
+// layers that are not part of the World (e.g., Approach, Behavior), so they are left free.
+func (ev *WorldEnv) State(element string) etensor.Tensor {
+	if st, has := ev.Obs[element]; has {
+		return st
+	}
+	return nil
+}
+
+// Action records the Behavior chosen by the network, as the most active unit
+// of the given Behavior pattern.  Its effects are applied on the next Step.
+func (ev *WorldEnv) Action(element string, input etensor.Tensor) {
+	if element != "Behavior" {
+		return
+	}
+	_, max, _, maxi := input.Range()
+	if max <= 0 {
+		maxi = -1
+	}
+	ev.Behavior.Set(maxi)
+}
+
+// Compile-time check that implements Env interface
+var _ env.Env = (*WorldEnv)(nil)
+
+// ClipUnit limits given value to the 0-1 range
+func ClipUnit(v float32) float32 {
+	switch {
+	case v < 0:
+		return 0
+	case v > 1:
+		return 1
+	}
+	return v
+}
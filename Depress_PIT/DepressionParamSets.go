@@ -154,12 +154,38 @@ var ParamSets = params.Sets{
 					"Prjn.WtScale.Rel": "0.3",
 				}},
 		},
-		//		"Sim": &params.Sheet{ // sim params apply to sim object
-		//			{Sel: "Sim", Desc: "best params always finish in this time",
-		//				Params: params.Params{
-		//					"Sim.MaxEpcs": "100",
-		//				}},
-		//		},
+		"Sim": &params.Sheet{ // sim params apply to sim object
+			{Sel: "Sim", Desc: "slow adaptation of the tonic VTA drive -- time constant in trials, and range",
+				Params: params.Params{
+					"Sim.TonicDA.Tau": "200",
+					"Sim.TonicDA.Min": "0.1",
+					"Sim.TonicDA.Max": "0.6",
+				}},
+		},
+	}},
+	{Name: "Stress", Desc: "DyDA input is set from chronic Stress accumulated from aversive experience, instead of from the input patterns", Sheets: params.Sheets{
+		"Sim": &params.Sheet{
+			{Sel: "Sim", Desc: "chronic stress accumulator on",
+				Params: params.Params{
+					"Sim.Stress.On": "true",
+				}},
+		},
+	}},
+	{Name: "TonicDA", Desc: "tonic VTA drive adapts to the recent history of Reward and DyDA, instead of the fixed VTA Act.Noise.Mean baseline", Sheets: params.Sheets{
+		"Sim": &params.Sheet{
+			{Sel: "Sim", Desc: "adaptation of the tonic VTA drive on",
+				Params: params.Params{
+					"Sim.TonicDA.On": "true",
+				}},
+		},
+	}},
+	{Name: "RPEGate", Desc: "learning in the Approach pathway is gated by the reward prediction error -- the Prjn Learn.Lrate is the rate at the maximum RPE", Sheets: params.Sheets{
+		"Sim": &params.Sheet{
+			{Sel: "Sim", Desc: "RPE gating of the Approach pathway Prjns on",
+				Params: params.Params{
+					"Sim.RPE.On": "true",
+				}},
+		},
 	}},
 	// 	},
 	//		"Sim": &params.Sheet{ // sim params apply to sim object
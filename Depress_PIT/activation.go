@@ -0,0 +1,207 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/goki/ki/kit"
+)
+
+// ActivationParams are the parameters of a behavioral activation therapy
+// intervention in the closed-loop World: from StartDay, for Days days, the
+// Behaviors are scheduled PerDay times per day, at evenly spaced time steps,
+// and promoted according to the Mode.  The daily VTA, DyDA and Approach
+// activity over the days before, during and after the intervention are
+// logged to compare recovery trajectories.
+type ActivationParams struct {
+	On        bool       `desc:"if true, the intervention is applied in the World"`
+	Mode      ActivModes `viewif:"On" desc:"how the scheduled Behaviors are promoted"`
+	Behaviors []int      `viewif:"On" desc:"indexes of the Behaviors of the intervention (e.g., 0 = Hngt), scheduled in turn"`
+	PerDay    int        `viewif:"On" def:"2" desc:"number of times per day that a Behavior is scheduled"`
+	StartDay  int        `viewif:"On" def:"2" desc:"first day of the intervention"`
+	Days      int        `viewif:"On" def:"5" desc:"number of days of the intervention -- 0 = through the end of the simulation"`
+	Bias      float32    `viewif:"Mode=ActBias" def:"0.5" desc:"activity added to the scheduled Behavior before a Behavior is selected"`
+	CostMult  float32    `viewif:"Mode=ActLowCost" def:"0" desc:"multiplier on the Cost of the Behaviors on the days of the intervention"`
+	Cur       int        `inactive:"+" desc:"Behavior scheduled on the current time step -- -1 = none"`
+	Day       int        `inactive:"+" desc:"day of the current row of the daily sums"`
+	sum       ActivDay
+}
+
+func (ap *ActivationParams) Defaults() {
+	ap.Mode = ActForce
+	ap.Behaviors = []int{0}
+	ap.PerDay = 2
+	ap.StartDay = 2
+	ap.Days = 5
+	ap.Bias = 0.5
+	ap.CostMult = 0
+	ap.Cur = -1
+	ap.Day = -1
+}
+
+// Active returns whether the intervention is applied on the given day
+func (ap *ActivationParams) Active(day int) bool {
+	return ap.On && day >= ap.StartDay && (ap.Days <= 0 || day < ap.StartDay+ap.Days)
+}
+
+// Schedule sets the Behavior scheduled on the current time step of the World,
+// and the Cost of the Behaviors for the current day
+func (ap *ActivationParams) Schedule(ev *WorldEnv) {
+	ap.Cur = -1
+	day, tick, nticks := ev.Epoch.Cur, ev.Tick.Cur, ev.Tick.Max
+	active := ap.Active(day)
+	ap.SetCost(ev, active && ap.Mode == ActLowCost)
+	if !active || ap.Mode == ActLowCost || len(ap.Behaviors) == 0 || ap.PerDay <= 0 || nticks <= 0 {
+		return
+	}
+	for k := 0; k < ap.PerDay; k++ {
+		if tick == k*nticks/ap.PerDay {
+			ap.Cur = ap.Behaviors[k%len(ap.Behaviors)]
+			return
+		}
+	}
+}
+
+// SetCost sets the Cost of the Behaviors in the State of the World, from the
+// Cost column of the Effects, multiplied by CostMult if low is true
+func (ap *ActivationParams) SetCost(ev *WorldEnv, low bool) {
+	st, has := ev.States[ev.CostLay]
+	if !has || ev.Effects == nil || ev.Effects.ColByName("Cost") == nil {
+		return
+	}
+	for _, beh := range ap.Behaviors {
+		if beh < 0 || beh >= len(st.Values) || beh >= ev.Effects.Rows {
+			continue
+		}
+		cost := float32(ev.Effects.CellFloat("Cost", beh))
+		if low {
+			cost *= ap.CostMult
+		}
+		st.Values[beh] = cost
+		if ob, has := ev.Obs[ev.CostLay]; has {
+			ob.Values[beh] = cost
+		}
+	}
+}
+
+// BiasActs adds the Bias to the activity of the scheduled Behavior, before selection
+func (ap *ActivationParams) BiasActs(acts []float32) {
+	if ap.Mode == ActBias && ap.Cur >= 0 && ap.Cur < len(acts) {
+		acts[ap.Cur] += ap.Bias
+	}
+}
+
+// Force returns the scheduled Behavior in place of the selected one, if forced
+func (ap *ActivationParams) Force(beh int) int {
+	if ap.Mode == ActForce && ap.Cur >= 0 {
+		return ap.Cur
+	}
+	return beh
+}
+
+// ConfigLog configures the table with one row per day
+func (ap *ActivationParams) ConfigLog(dt *etable.Table) {
+	sch := etable.Schema{
+		{"ParamSet", etensor.STRING, nil, nil},
+		{"Day", etensor.INT64, nil, nil},
+		{"Active", etensor.INT64, nil, nil},
+		{"NScheduled", etensor.INT64, nil, nil},
+		{"NChosen", etensor.INT64, nil, nil},
+		{"VTA", etensor.FLOAT64, nil, nil},
+		{"DyDA", etensor.FLOAT64, nil, nil},
+		{"Approach", etensor.FLOAT64, nil, nil},
+		{"Stress", etensor.FLOAT64, nil, nil},
+	}
+	dt.SetFromSchema(sch, 0)
+	dt.SetMetaData("name", "ActivationLog")
+	dt.SetMetaData("desc", "behavioral activation: mean VTA, DyDA and Approach activity per day, Stress at the end of the day, and the number of times an intervention Behavior was scheduled and chosen")
+	ap.Day = -1
+	ap.sum = ActivDay{}
+}
+
+// Accum adds the activities of the current time step, with the chosen
+// Behavior, to the sums of the given day, first adding a row for the previous
+// day to the log if the day has changed
+func (ap *ActivationParams) Accum(dt *etable.Table, paramSet string, day, beh int, vta, dyda, appr, stress float32) {
+	if day != ap.Day {
+		ap.LogDay(dt, paramSet)
+		ap.Day = day
+	}
+	sm := &ap.sum
+	if ap.Cur >= 0 {
+		sm.NSched++
+		if beh == ap.Cur {
+			sm.NChosen++
+		}
+	}
+	sm.VTA += float64(vta)
+	sm.DyDA += float64(dyda)
+	sm.Approach += float64(appr)
+	sm.Stress = float64(stress)
+	sm.N++
+}
+
+// LogDay adds a row to the log with the means of the current day, if any,
+// and resets the sums
+func (ap *ActivationParams) LogDay(dt *etable.Table, paramSet string) {
+	sm := &ap.sum
+	if sm.N == 0 {
+		return
+	}
+	n := float64(sm.N)
+	row := dt.Rows
+	dt.SetNumRows(row + 1)
+	dt.SetCellString("ParamSet", row, paramSet)
+	dt.SetCellFloat("Day", row, float64(ap.Day))
+	act := 0.0
+	if ap.Active(ap.Day) {
+		act = 1
+	}
+	dt.SetCellFloat("Active", row, act)
+	dt.SetCellFloat("NScheduled", row, float64(sm.NSched))
+	dt.SetCellFloat("NChosen", row, float64(sm.NChosen))
+	dt.SetCellFloat("VTA", row, sm.VTA/n)
+	dt.SetCellFloat("DyDA", row, sm.DyDA/n)
+	dt.SetCellFloat("Approach", row, sm.Approach/n)
+	dt.SetCellFloat("Stress", row, sm.Stress)
+	ap.sum = ActivDay{}
+}
+
+// ActivDay has the sums of the activities over the time steps of one day
+type ActivDay struct {
+	N        int
+	NSched   int
+	NChosen  int
+	VTA      float64
+	DyDA     float64
+	Approach float64
+	Stress   float64
+}
+
+// ActivModes are the ways that the Behaviors of an intervention are promoted
+type ActivModes int32
+
+//go:generate stringer -type=ActivModes
+
+var KiT_ActivModes = kit.Enums.AddEnum(ActivModesN, kit.NotBitFlag, nil)
+
+func (ev ActivModes) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *ActivModes) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// The intervention modes
+const (
+	// ActForce selects the scheduled Behavior in place of the one selected by the network
+	ActForce ActivModes = iota
+
+	// ActBias adds Bias to the activity of the scheduled Behavior before selection
+	ActBias
+
+	// ActLowCost multiplies the Cost of the Behaviors by CostMult on the days of
+	// the intervention, so that they are chosen more freely
+	ActLowCost
+
+	ActivModesN
+)
@@ -0,0 +1,183 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/goki/gi/gi"
+)
+
+// InitPhase is the name of the checkpoint of the initial weights of a run,
+// saved by NewRun, which the first phase of the curriculum starts from --
+// not saved when resuming from a Start checkpoint
+const InitPhase = "INIT"
+
+// StaleLock is the age at which the lock file of the manifest is taken to be
+// left over from a process that did not finish saving, and is removed -- the
+// lock is only held while the manifest is read and written
+const StaleLock = time.Minute
+
+// Checkpoints manages the weights files saved at the start of each run and at
+// the end of each phase of the training curriculum, with a Manifest of all of
+// them, saved as checkpoints.tsv in Dir.  Any phase can start from any of them.
+// Several runs can share the same Dir: the manifest is re-read and merged,
+// under a lock file, before each checkpoint is added to it.
+type Checkpoints struct {
+	Dir      string        `desc:"directory for the checkpoint files and the manifest -- empty = current directory"`
+	Start    string        `desc:"checkpoint file that the first phase of the curriculum starts from -- empty = the INIT checkpoint of the run -- use the same file to compare curricula from identical starting weights"`
+	Manifest *etable.Table `view:"no-inline" desc:"manifest of all the checkpoints: File, Run, Phase, Epoch and ParamSet"`
+}
+
+// ManifestFile returns the file name of the manifest
+func (ck *Checkpoints) ManifestFile() string {
+	return filepath.Join(ck.Dir, "checkpoints.tsv")
+}
+
+// Open opens the existing manifest in Dir, if there is one,
+// otherwise starts a new one
+func (ck *Checkpoints) Open() error {
+	unlock, err := ck.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	ck.Manifest, err = ck.ReadManifest()
+	return err
+}
+
+// NewManifest returns a new empty manifest table
+func NewManifest() *etable.Table {
+	sch := etable.Schema{
+		{"File", etensor.STRING, nil, nil},
+		{"Run", etensor.INT64, nil, nil},
+		{"Phase", etensor.STRING, nil, nil},
+		{"Epoch", etensor.INT64, nil, nil},
+		{"ParamSet", etensor.STRING, nil, nil},
+	}
+	dt := &etable.Table{}
+	dt.SetFromSchema(sch, 0)
+	dt.SetMetaData("name", "Checkpoints")
+	dt.SetMetaData("desc", "manifest of the weights checkpoints")
+	return dt
+}
+
+// ReadManifest reads the manifest file in Dir, or returns a new empty
+// manifest if there is none -- the lock must be held
+func (ck *Checkpoints) ReadManifest() (*etable.Table, error) {
+	fnm := ck.ManifestFile()
+	if _, err := os.Stat(fnm); err != nil {
+		return NewManifest(), nil
+	}
+	dt := &etable.Table{}
+	if err := dt.OpenCSV(gi.FileName(fnm), etable.Tab); err != nil {
+		return nil, err
+	}
+	dt.SetMetaData("name", "Checkpoints")
+	dt.SetMetaData("desc", "manifest of the weights checkpoints")
+	return dt, nil
+}
+
+// Lock creates the lock file of the manifest, waiting for any other process
+// that holds it, and returns the function that removes it
+func (ck *Checkpoints) Lock() (func(), error) {
+	if ck.Dir != "" {
+		if err := os.MkdirAll(ck.Dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+	fnm := ck.ManifestFile() + ".lock"
+	for {
+		f, err := os.OpenFile(fnm, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(fnm) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if fi, err := os.Stat(fnm); err == nil && time.Since(fi.ModTime()) > StaleLock {
+			if err := os.Remove(fnm); err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("Checkpoints: could not remove stale lock file: %v", err)
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// SetRow sets the row of the manifest for the given checkpoint file, adding
+// it if it is not already there
+func SetRow(dt *etable.Table, fpath string, run int, phase string, epc int, paramSet string) {
+	row := dt.Rows
+	for r := 0; r < dt.Rows; r++ {
+		if dt.CellString("File", r) == fpath {
+			row = r
+			break
+		}
+	}
+	if row == dt.Rows {
+		dt.SetNumRows(row + 1)
+	}
+	dt.SetCellString("File", row, fpath)
+	dt.SetCellFloat("Run", row, float64(run))
+	dt.SetCellString("Phase", row, phase)
+	dt.SetCellFloat("Epoch", row, float64(epc))
+	dt.SetCellString("ParamSet", row, paramSet)
+}
+
+// Save saves the network weights to the given file name in Dir, and adds it
+// to the Manifest, or updates its row if it is already there, and saves the
+// Manifest, merged with the rows added by any other runs sharing the same Dir
+// since it was last read.  Returns the path of the file.
+func (ck *Checkpoints) Save(net emer.Network, fnm string, run int, phase string, epc int, paramSet string) (string, error) {
+	unlock, err := ck.Lock()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	fpath := filepath.Join(ck.Dir, fnm)
+	if err := net.SaveWtsJSON(gi.FileName(fpath)); err != nil {
+		return "", err
+	}
+	dt, err := ck.ReadManifest()
+	if err != nil {
+		return "", err
+	}
+	if old := ck.Manifest; old != nil {
+		for r := 0; r < old.Rows; r++ {
+			SetRow(dt, old.CellString("File", r), int(old.CellFloat("Run", r)), old.CellString("Phase", r), int(old.CellFloat("Epoch", r)), old.CellString("ParamSet", r))
+		}
+	}
+	SetRow(dt, fpath, run, phase, epc, paramSet)
+	ck.Manifest = dt
+	return fpath, dt.SaveCSV(gi.FileName(ck.ManifestFile()), etable.Tab, etable.Headers)
+}
+
+// Latest returns the file of the most recent checkpoint of the given phase
+// in the given run, or "" if there is none
+func (ck *Checkpoints) Latest(run int, phase string) string {
+	dt := ck.Manifest
+	for row := dt.Rows - 1; row >= 0; row-- {
+		if int(dt.CellFloat("Run", row)) == run && dt.CellString("Phase", row) == phase {
+			return dt.CellString("File", row)
+		}
+	}
+	return ""
+}
+
+// Find returns the checkpoint file for the given name, which is either the
+// name of a phase with a checkpoint in the given run, or else a file name
+func (ck *Checkpoints) Find(run int, nm string) string {
+	if fnm := ck.Latest(run, nm); fnm != "" {
+		return fnm
+	}
+	return nm
+}
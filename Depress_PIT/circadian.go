@@ -0,0 +1,144 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// WorldClock is the simulated clock time of day in the World
+type WorldClock struct {
+	StartHour    float32 `def:"6" desc:"hour of the day (0-24) at the first time step"`
+	HoursPerTick float32 `desc:"hours of simulated time per time step -- 0 = one day of 24 hours per pass through the Changes table"`
+	Hour         float32 `inactive:"+" desc:"current hour of the day (0-24)"`
+}
+
+func (cl *WorldClock) Defaults() {
+	cl.StartHour = 6
+	cl.HoursPerTick = 0
+}
+
+// Set sets the Hour for given total number of time steps, with given number
+// of time steps per day (used if HoursPerTick is 0)
+func (cl *WorldClock) Set(ticks, ticksPerDay int) {
+	hpt := cl.HoursPerTick
+	if hpt == 0 && ticksPerDay > 0 {
+		hpt = 24 / float32(ticksPerDay)
+	}
+	cl.Hour = float32(math.Mod(float64(cl.StartHour+float32(ticks)*hpt), 24))
+}
+
+// HourIn returns true if given hour is in the range from open to close,
+// which can wrap around midnight (e.g., 22 to 6)
+func HourIn(hr, open, close float32) bool {
+	if open <= close {
+		return hr >= open && hr < close
+	}
+	return hr >= open || hr < close
+}
+
+// ValidateCircadian checks the Circadian table, which has one row per
+// modulated unit, with these columns:
+//   - Name: name of the row, for display
+//   - Layer: name of the State layer
+//   - Unit: index of the unit within the layer
+//   - Mode: Incr to multiply the Incr of a DynLay unit by 1 + Amp * cos(2 pi (Hour - Peak) / 24),
+//     or Gate to present the feature only between the Open and Close hours
+//   - Amp, Peak: amplitude (0-1) and hour of peak for Incr
+//   - Open, Close: hours for Gate
+func (ev *WorldEnv) ValidateCircadian() error {
+	if ev.Circadian == nil {
+		return nil
+	}
+	for _, cnm := range []string{"Layer", "Unit", "Mode", "Amp", "Peak", "Open", "Close"} {
+		if ev.Circadian.ColByName(cnm) == nil {
+			return fmt.Errorf("WorldEnv: %v Circadian table has no %v column", ev.Nm, cnm)
+		}
+	}
+	for ri := 0; ri < ev.Circadian.Rows; ri++ {
+		switch md := ev.Circadian.CellString("Mode", ri); md {
+		case "Incr", "Gate":
+		default:
+			return fmt.Errorf("WorldEnv: %v Circadian table row %v has invalid Mode: %v -- must be Incr or Gate", ev.Nm, ri, md)
+		}
+	}
+	return nil
+}
+
+// InitCircadian initializes the Clock and the CircVals
+func (ev *WorldEnv) InitCircadian() {
+	ev.Clock.Set(0, ev.Tick.Max)
+	ev.CircVals = nil
+	if ev.Circadian == nil {
+		return
+	}
+	ev.CircVals = make([]float32, ev.Circadian.Rows)
+	for i := range ev.CircVals {
+		ev.CircVals[i] = 1
+	}
+}
+
+// UpdateCircadian updates the Clock for the current time step, and the CircVals
+// for the resulting time of day
+func (ev *WorldEnv) UpdateCircadian() {
+	ev.Clock.Set(ev.Epoch.Cur*ev.Tick.Max+ev.Tick.Cur, ev.Tick.Max)
+	if ev.Circadian == nil {
+		return
+	}
+	hr := ev.Clock.Hour
+	for ri := range ev.CircVals {
+		switch ev.Circadian.CellString("Mode", ri) {
+		case "Incr":
+			amp := ev.Circadian.CellFloat("Amp", ri)
+			pk := ev.Circadian.CellFloat("Peak", ri)
+			mod := 1 + amp*math.Cos(2*math.Pi*(float64(hr)-pk)/24)
+			ev.CircVals[ri] = float32(math.Max(mod, 0))
+		case "Gate":
+			ev.CircVals[ri] = 0
+			if HourIn(hr, float32(ev.Circadian.CellFloat("Open", ri)), float32(ev.Circadian.CellFloat("Close", ri))) {
+				ev.CircVals[ri] = 1
+			}
+		}
+	}
+}
+
+// IncrMod returns the multiplier on the Incr for given unit of the DynLay,
+// from the Incr rows of the Circadian table -- 1 if none
+func (ev *WorldEnv) IncrMod(unit int) float32 {
+	mod := float32(1)
+	if ev.Circadian == nil {
+		return mod
+	}
+	for ri, cv := range ev.CircVals {
+		if ev.Circadian.CellString("Mode", ri) != "Incr" || ev.Circadian.CellString("Layer", ri) != ev.DynLay {
+			continue
+		}
+		if int(ev.Circadian.CellFloat("Unit", ri)) == unit {
+			mod *= cv
+		}
+	}
+	return mod
+}
+
+// UpdateObs sets the observed State presented to the network from the State,
+// with the Gate rows of the Circadian table applied
+func (ev *WorldEnv) UpdateObs() {
+	for lnm, st := range ev.States {
+		copy(ev.Obs[lnm].Values, st.Values)
+	}
+	if ev.Circadian == nil {
+		return
+	}
+	for ri, cv := range ev.CircVals {
+		if ev.Circadian.CellString("Mode", ri) != "Gate" {
+			continue
+		}
+		ob, has := ev.Obs[ev.Circadian.CellString("Layer", ri)]
+		if u := int(ev.Circadian.CellFloat("Unit", ri)); has && u >= 0 && u < len(ob.Values) {
+			ob.Values[u] *= cv
+		}
+	}
+}
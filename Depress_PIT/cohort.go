@@ -0,0 +1,180 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/emer/emergent/erand"
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// CohortParams are the parameters of a synthetic cohort of N virtual
+// individuals, whose traits are sampled from the given distributions: the
+// chronic motive biases on each unit of the MBApp and MBAv input layers, the
+// baseline tonic VTA drive, and the DyDA sensitivity to Stress.  Each
+// individual is trained and tested from the same initial weights, so that the
+// differences in outcome are due to the traits alone.
+type CohortParams struct {
+	N        int             `def:"20" desc:"number of individuals in the cohort"`
+	Seed     int64           `desc:"random seed for sampling the traits -- the same seed gives the same cohort"`
+	MBApp    erand.RndParams `view:"inline" desc:"distribution of the activity of each MBApp unit -- clipped to 0-1"`
+	MBAv     erand.RndParams `view:"inline" desc:"distribution of the activity of each MBAv unit -- clipped to 0-1"`
+	VTABase  erand.RndParams `view:"inline" desc:"distribution of the baseline tonic VTA drive -- clipped to 0-1"`
+	DyDAGain erand.RndParams `view:"inline" desc:"distribution of the DyDA sensitivity: gain on Stress for the DyDA input -- at least 0"`
+	Days     int             `def:"2" desc:"number of days of the closed-loop World to simulate after training, for the outcomes -- 0 = none"`
+}
+
+func (cp *CohortParams) Defaults() {
+	cp.N = 20
+	cp.Seed = 1
+	cp.MBApp = erand.RndParams{Dist: erand.Uniform, Mean: 0.25, Var: 0.25}
+	cp.MBAv = erand.RndParams{Dist: erand.Uniform, Mean: 0.25, Var: 0.25}
+	cp.VTABase = erand.RndParams{Dist: erand.Gaussian, Mean: 0.4, Var: 0.05}
+	cp.DyDAGain = erand.RndParams{Dist: erand.Gaussian, Mean: 1, Var: 0.2}
+	cp.Days = 2
+}
+
+// Individual has the traits of one individual of a cohort
+type Individual struct {
+	ID       int       `desc:"index of the individual in the cohort"`
+	MBApp    []float32 `desc:"activity of each MBApp unit"`
+	MBAv     []float32 `desc:"activity of each MBAv unit"`
+	VTABase  float32   `desc:"baseline tonic VTA drive"`
+	DyDAGain float32   `desc:"gain on Stress for the DyDA input"`
+}
+
+// Sample returns the N individuals of the cohort, with nApp MBApp units and
+// nAv MBAv units, sampled with the Seed, from their own random source, so the
+// global one used for training is not changed.
+func (cp *CohortParams) Sample(nApp, nAv int) []*Individual {
+	rnd := rand.New(rand.NewSource(cp.Seed))
+	inds := make([]*Individual, cp.N)
+	for i := range inds {
+		ind := &Individual{ID: i, MBApp: make([]float32, nApp), MBAv: make([]float32, nAv)}
+		for u := range ind.MBApp {
+			ind.MBApp[u] = ClipUnit(float32(GenRnd(&cp.MBApp, rnd)))
+		}
+		for u := range ind.MBAv {
+			ind.MBAv[u] = ClipUnit(float32(GenRnd(&cp.MBAv, rnd)))
+		}
+		ind.VTABase = ClipUnit(float32(GenRnd(&cp.VTABase, rnd)))
+		ind.DyDAGain = float32(math.Max(0, GenRnd(&cp.DyDAGain, rnd)))
+		inds[i] = ind
+	}
+	return inds
+}
+
+// GenRnd generates a random value from the distribution of the given params,
+// as erand.RndParams.Gen does, but from the given random source
+func GenRnd(rp *erand.RndParams, rnd *rand.Rand) float64 {
+	src := rndSrc{rnd}
+	switch rp.Dist {
+	case erand.Uniform:
+		return rp.Mean + rp.Var*2*(rnd.Float64()-0.5)
+	case erand.Binomial:
+		return rp.Mean + distuv.Binomial{N: rp.Par, P: rp.Var, Src: src}.Rand()
+	case erand.Poisson:
+		return rp.Mean + distuv.Poisson{Lambda: rp.Var, Src: src}.Rand()
+	case erand.Gamma:
+		return rp.Mean + distuv.Gamma{Alpha: rp.Par, Beta: rp.Var, Src: src}.Rand()
+	case erand.Gaussian:
+		return rp.Mean + rp.Var*rnd.NormFloat64()
+	case erand.Beta:
+		x1 := distuv.Gamma{Alpha: 1, Beta: rp.Var, Src: src}.Rand()
+		x2 := distuv.Gamma{Alpha: 1, Beta: rp.Par, Src: src}.Rand()
+		return rp.Mean + x1/(x1+x2)
+	}
+	return rp.Mean
+}
+
+// rndSrc is a math/rand source as the random source of the gonum distributions
+type rndSrc struct {
+	rnd *rand.Rand
+}
+
+func (rs rndSrc) Uint64() uint64   { return rs.rnd.Uint64() }
+func (rs rndSrc) Seed(seed uint64) { rs.rnd.Seed(int64(seed)) }
+
+// SetBiases sets the MBApp and MBAv columns of every row of the given
+// pattern table, if it has them, to the motive biases of the individual
+func (ind *Individual) SetBiases(dt *etable.Table) {
+	for lnm, vals := range map[string][]float32{"MBApp": ind.MBApp, "MBAv": ind.MBAv} {
+		if dt.ColByName(lnm) == nil {
+			continue
+		}
+		for row := 0; row < dt.Rows; row++ {
+			tsr := dt.CellTensor(lnm, row)
+			for i := 0; i < tsr.Len() && i < len(vals); i++ {
+				tsr.SetFloat1D(i, float64(vals[i]))
+			}
+		}
+	}
+}
+
+// ConfigSummary configures the table with one row per individual, with
+// its traits and outcomes, for nApp MBApp units and nAv MBAv units
+func (cp *CohortParams) ConfigSummary(dt *etable.Table, nApp, nAv int) {
+	sch := etable.Schema{
+		{"ID", etensor.INT64, nil, nil},
+		{"MBApp", etensor.FLOAT32, []int{1, nApp}, []string{"Y", "X"}},
+		{"MBAv", etensor.FLOAT32, []int{1, nAv}, []string{"Y", "X"}},
+		{"VTABase", etensor.FLOAT64, nil, nil},
+		{"DyDAGain", etensor.FLOAT64, nil, nil},
+		{"Epochs", etensor.INT64, nil, nil},
+		{"TrlsToCrit", etensor.INT64, nil, nil},
+		{"TestPctErr", etensor.FLOAT64, nil, nil},
+		{"TestApproach", etensor.FLOAT64, nil, nil},
+		{"TestAvoid", etensor.FLOAT64, nil, nil},
+		{"WorldStress", etensor.FLOAT64, nil, nil},
+		{"WorldVTA", etensor.FLOAT64, nil, nil},
+		{"WorldApproach", etensor.FLOAT64, nil, nil},
+		{"WorldTonicDA", etensor.FLOAT64, nil, nil},
+	}
+	dt.SetFromSchema(sch, 0)
+	dt.SetMetaData("name", "Cohort")
+	dt.SetMetaData("desc", "synthetic cohort: traits of each individual, with the outcomes of training, testing and the closed-loop World")
+}
+
+// AddSummary adds a row for the individual to the summary table, with its
+// traits -- the outcomes are set by the caller
+func (ind *Individual) AddSummary(dt *etable.Table) int {
+	row := dt.Rows
+	dt.SetNumRows(row + 1)
+	dt.SetCellFloat("ID", row, float64(ind.ID))
+	for lnm, vals := range map[string][]float32{"MBApp": ind.MBApp, "MBAv": ind.MBAv} {
+		tsr := dt.CellTensor(lnm, row)
+		for i := 0; i < tsr.Len() && i < len(vals); i++ {
+			tsr.SetFloat1D(i, float64(vals[i]))
+		}
+	}
+	dt.SetCellFloat("VTABase", row, float64(ind.VTABase))
+	dt.SetCellFloat("DyDAGain", row, float64(ind.DyDAGain))
+	return row
+}
+
+// SaveBiases returns copies of the MBApp and MBAv columns of the given
+// pattern tables, for RestoreBiases
+func SaveBiases(tbls []*etable.Table) map[etensor.Tensor]etensor.Tensor {
+	cols := make(map[etensor.Tensor]etensor.Tensor)
+	for _, dt := range tbls {
+		for _, lnm := range []string{"MBApp", "MBAv"} {
+			if col := dt.ColByName(lnm); col != nil {
+				cols[col] = col.Clone()
+			}
+		}
+	}
+	return cols
+}
+
+// RestoreBiases sets back the columns saved by SaveBiases
+func RestoreBiases(cols map[etensor.Tensor]etensor.Tensor) {
+	for col, orig := range cols {
+		col.CopyFrom(orig)
+	}
+}
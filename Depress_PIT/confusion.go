@@ -0,0 +1,122 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// BehConfusion is the confusion matrix of the target Behavior of each test
+// trial vs. the Behavior produced by the network (ArgMaxBeh of the Behavior
+// minus phase activity), and the frequency of each Behavior as the target and
+// as produced, computed from the TargBeh and RespBeh columns of the test trial
+// log.  They show which Behaviors a parameterization systematically replaces,
+// and by which others.
+type BehConfusion struct {
+	Names  []string      `desc:"names of the Behaviors"`
+	Matrix *etable.Table `view:"no-inline" desc:"one row per target Behavior: number of trials, and the proportion of them on which each Behavior, or None, was produced"`
+	Freqs  *etable.Table `view:"no-inline" desc:"one row per Behavior, then None: number and proportion of the trials with it as the target, and as produced"`
+}
+
+// Config configures the tables for the Behaviors with given names
+func (bc *BehConfusion) Config(names []string) {
+	bc.Names = names
+	sch := etable.Schema{
+		{"Target", etensor.STRING, nil, nil},
+		{"N", etensor.INT64, nil, nil},
+	}
+	for _, nm := range names {
+		sch = append(sch, etable.Column{Name: nm, Type: etensor.FLOAT64})
+	}
+	sch = append(sch, etable.Column{Name: "None", Type: etensor.FLOAT64})
+	bc.Matrix = &etable.Table{}
+	bc.Matrix.SetFromSchema(sch, len(names))
+	bc.Matrix.SetMetaData("name", "BehConfusion")
+	bc.Matrix.SetMetaData("desc", "confusion matrix of the target Behavior (rows) vs. the produced Behavior (columns) over the test trials")
+	for b, nm := range names {
+		bc.Matrix.SetCellString("Target", b, nm)
+	}
+
+	sch = etable.Schema{
+		{"Behavior", etensor.STRING, nil, nil},
+		{"NTarget", etensor.INT64, nil, nil},
+		{"NProduced", etensor.INT64, nil, nil},
+		{"TargetFreq", etensor.FLOAT64, nil, nil},
+		{"ProducedFreq", etensor.FLOAT64, nil, nil},
+	}
+	bc.Freqs = &etable.Table{}
+	bc.Freqs.SetFromSchema(sch, len(names)+1)
+	bc.Freqs.SetMetaData("name", "BehChoiceFreq")
+	bc.Freqs.SetMetaData("desc", "frequency of each Behavior as the target and as produced over the test trials")
+	for b, nm := range names {
+		bc.Freqs.SetCellString("Behavior", b, nm)
+	}
+	bc.Freqs.SetCellString("Behavior", len(names), "None")
+}
+
+// Compute computes the tables from the TargBeh and RespBeh columns of the
+// rows of the given test trial log -- -1 is no Behavior
+func (bc *BehConfusion) Compute(dt *etable.Table) {
+	nb := len(bc.Names)
+	if dt.ColByName("TargBeh") == nil || dt.ColByName("RespBeh") == nil {
+		return
+	}
+	cnts := make([][]int, nb) // target x produced, with None last
+	for b := range cnts {
+		cnts[b] = make([]int, nb+1)
+	}
+	ntarg := make([]int, nb+1)
+	nresp := make([]int, nb+1)
+	for row := 0; row < dt.Rows; row++ {
+		targ := int(dt.CellFloat("TargBeh", row))
+		resp := int(dt.CellFloat("RespBeh", row))
+		if targ < 0 || targ >= nb {
+			targ = nb
+		}
+		if resp < 0 || resp >= nb {
+			resp = nb
+		}
+		ntarg[targ]++
+		nresp[resp]++
+		if targ < nb {
+			cnts[targ][resp]++
+		}
+	}
+	for b := 0; b < nb; b++ {
+		bc.Matrix.SetCellFloat("N", b, float64(ntarg[b]))
+		for r := 0; r <= nb; r++ {
+			p := 0.0
+			if ntarg[b] > 0 {
+				p = float64(cnts[b][r]) / float64(ntarg[b])
+			}
+			bc.Matrix.SetCellFloat(bc.Matrix.ColNames[2+r], b, p)
+		}
+	}
+	for b := 0; b <= nb; b++ {
+		bc.Freqs.SetCellFloat("NTarget", b, float64(ntarg[b]))
+		bc.Freqs.SetCellFloat("NProduced", b, float64(nresp[b]))
+		tf, rf := 0.0, 0.0
+		if dt.Rows > 0 {
+			tf = float64(ntarg[b]) / float64(dt.Rows)
+			rf = float64(nresp[b]) / float64(dt.Rows)
+		}
+		bc.Freqs.SetCellFloat("TargetFreq", b, tf)
+		bc.Freqs.SetCellFloat("ProducedFreq", b, rf)
+	}
+}
+
+// ArgMaxBeh returns the index of the most active Behavior -- -1 if none is active
+func ArgMaxBeh(vals []float32) int {
+	maxi := -1
+	var max float32
+	for i, v := range vals {
+		if v > max {
+			maxi = i
+			max = v
+		}
+	}
+	return maxi
+}
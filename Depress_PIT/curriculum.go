@@ -0,0 +1,234 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/etable/etable"
+)
+
+// CurricPhase is one phase of a training curriculum, read from one row of a
+// curriculum table (e.g., InstrThenPvlv.tsv), which has these columns:
+//   - Training: name of the phase (e.g., INSTRUMENTAL, PAVLOV, EXTINCTION)
+//   - Patterns: file name of the pattern table to train and test on
+//   - MaxEpoch: maximum number of epochs of training
+//   - NZeroStop: stop after this many epochs with zero SSE -- 0 or less = no early stop (optional)
+//   - Inputs: space-separated names of the layers that are Input (optional)
+//   - Targets: space-separated names of the layers that are Target (optional)
+//   - Lesion: space-separated names of the layers that are lesioned (optional)
+//   - Start: checkpoint that the phase starts from: the name of an earlier phase,
+//     INIT or a weights file -- empty = the end of the previous phase (optional)
+//   - Mix: name of a group of consecutive phases whose trials are interleaved
+//     within each epoch, as one step of the curriculum (optional)
+//   - Ratio: share of the trials of each epoch from this phase, relative to the
+//     other phases of its Mix -- 0 = in proportion to its patterns (optional)
+//   - Zero: space-separated names of the layers whose patterns are set to zero,
+//     e.g., Approach targets for extinction, or EnviroFeatures cues for
+//     reinstatement by unsignaled outcomes (optional)
+//   - Test: 1 = a test phase, e.g., of extinction, reinstatement or renewal,
+//     with MaxEpoch epochs (at least one) of testing trials, and no learning (optional)
+//   - Remap: name of a layer whose patterns are remapped by Perm, e.g., Behavior
+//     targets for reversal learning (optional)
+//   - Perm: space-separated permutation of the units of the Remap layer: unit i
+//     gets the value of unit Perm[i] in the patterns (optional)
+//
+// Layers not named in Inputs or Targets keep their type, and the layer types
+// and lesions are restored at the end of the phase.  The MaxEpoch, NZeroStop
+// and Start of a Mix are those of its first phase.
+type CurricPhase struct {
+	Name      string   `desc:"name of the phase"`
+	Patterns  string   `desc:"file name of the pattern table to train and test on"`
+	MaxEpcs   int      `desc:"maximum number of epochs of training"`
+	NZeroStop int      `desc:"if a positive number, training will stop after this many epochs with zero SSE"`
+	Inputs    []string `desc:"names of the layers that are Input during the phase"`
+	Targets   []string `desc:"names of the layers that are Target during the phase"`
+	Lesion    []string `desc:"names of the layers that are lesioned during the phase"`
+	Start     string   `desc:"checkpoint that the phase starts from -- empty = the end of the previous phase"`
+	Mix       string   `desc:"name of the group of consecutive phases whose trials are interleaved within each epoch -- empty = trained on its own"`
+	Ratio     float64  `desc:"share of the trials of each epoch from this phase, relative to the other phases of its Mix -- 0 = in proportion to its patterns"`
+	Zero      []string `desc:"names of the layers whose patterns are set to zero during the phase"`
+	Test      bool     `desc:"if true, a test phase, with testing trials and no learning"`
+	Remap     string   `desc:"name of the layer whose patterns are remapped by Perm during the phase"`
+	Perm      []int    `desc:"permutation of the units of the Remap layer: unit i gets the value of unit Perm[i] in the patterns"`
+}
+
+// CurricPhases returns the phases in all the rows of the curriculum table, in order
+func CurricPhases(dt *etable.Table) ([]*CurricPhase, error) {
+	for _, cnm := range []string{"Training", "Patterns", "MaxEpoch"} {
+		if dt.ColByName(cnm) == nil {
+			return nil, fmt.Errorf("CurricPhases: curriculum table has no %v column", cnm)
+		}
+	}
+	lays := func(cnm string, row int) []string {
+		if dt.ColByName(cnm) == nil {
+			return nil
+		}
+		return strings.Fields(dt.CellString(cnm, row))
+	}
+	phases := make([]*CurricPhase, dt.Rows)
+	for row := 0; row < dt.Rows; row++ {
+		cp := &CurricPhase{}
+		cp.Name = dt.CellString("Training", row)
+		cp.Patterns = dt.CellString("Patterns", row)
+		cp.MaxEpcs = int(dt.CellFloat("MaxEpoch", row))
+		cp.NZeroStop = -1
+		if dt.ColByName("NZeroStop") != nil {
+			cp.NZeroStop = int(dt.CellFloat("NZeroStop", row))
+		}
+		cp.Inputs = lays("Inputs", row)
+		cp.Targets = lays("Targets", row)
+		cp.Lesion = lays("Lesion", row)
+		if dt.ColByName("Start") != nil {
+			cp.Start = dt.CellString("Start", row)
+		}
+		if dt.ColByName("Mix") != nil {
+			cp.Mix = dt.CellString("Mix", row)
+		}
+		if dt.ColByName("Ratio") != nil {
+			cp.Ratio = dt.CellFloat("Ratio", row)
+		}
+		cp.Zero = lays("Zero", row)
+		if dt.ColByName("Test") != nil {
+			cp.Test = dt.CellFloat("Test", row) > 0
+		}
+		if dt.ColByName("Remap") != nil {
+			cp.Remap = dt.CellString("Remap", row)
+		}
+		for _, ps := range lays("Perm", row) {
+			pi, err := strconv.Atoi(ps)
+			if err != nil {
+				return nil, fmt.Errorf("CurricPhases: phase %v in row %v has an invalid Perm: %v", cp.Name, row, err)
+			}
+			cp.Perm = append(cp.Perm, pi)
+		}
+		if cp.Patterns == "" {
+			return nil, fmt.Errorf("CurricPhases: phase %v in row %v has no Patterns", cp.Name, row)
+		}
+		phases[row] = cp
+	}
+	return phases, nil
+}
+
+// CurricSteps groups the phases into the steps of the curriculum: each
+// phase on its own, or all the consecutive phases of the same Mix together
+func CurricSteps(phases []*CurricPhase) [][]*CurricPhase {
+	var steps [][]*CurricPhase
+	for i, cp := range phases {
+		if i > 0 && cp.Mix != "" && cp.Mix == phases[i-1].Mix {
+			steps[len(steps)-1] = append(steps[len(steps)-1], cp)
+			continue
+		}
+		steps = append(steps, []*CurricPhase{cp})
+	}
+	return steps
+}
+
+// Validate checks that all the layers named in the phase exist in the network
+func (cp *CurricPhase) Validate(net emer.Network) error {
+	if cp.Test && cp.Mix != "" {
+		return fmt.Errorf("CurricPhase %v: a Test phase cannot be in a Mix", cp.Name)
+	}
+	if (cp.Remap == "") != (len(cp.Perm) == 0) {
+		return fmt.Errorf("CurricPhase %v: Remap and Perm must be given together", cp.Name)
+	}
+	for i, n := 0, len(cp.Perm); i < n; i++ { // each unit exactly once
+		if pi := cp.Perm[i]; pi < 0 || pi >= n || cp.permIdx(pi) != i {
+			return fmt.Errorf("CurricPhase %v: Perm is not a permutation: %v", cp.Name, cp.Perm)
+		}
+	}
+	remap := []string{}
+	if cp.Remap != "" {
+		remap = append(remap, cp.Remap)
+	}
+	for _, lnms := range [][]string{cp.Inputs, cp.Targets, cp.Lesion, cp.Zero, remap} {
+		for _, lnm := range lnms {
+			if _, err := net.LayerByNameTry(lnm); err != nil {
+				return fmt.Errorf("CurricPhase %v: %v", cp.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// permIdx returns the first index in Perm with the given unit
+func (cp *CurricPhase) permIdx(unit int) int {
+	for i, pi := range cp.Perm {
+		if pi == unit {
+			return i
+		}
+	}
+	return -1
+}
+
+// PhasePats returns the patterns with the Zero layers set to zero and the
+// Remap layer remapped by Perm, as a copy, or the patterns themselves if
+// there is nothing to change
+func (cp *CurricPhase) PhasePats(pats *etable.Table) (*etable.Table, error) {
+	if len(cp.Zero) == 0 && cp.Remap == "" {
+		return pats, nil
+	}
+	dt := pats.Clone()
+	for _, lnm := range cp.Zero {
+		if col := dt.ColByName(lnm); col != nil {
+			col.SetZeros()
+		}
+	}
+	if cp.Remap == "" {
+		return dt, nil
+	}
+	if dt.ColByName(cp.Remap) == nil {
+		return nil, fmt.Errorf("CurricPhase %v: patterns have no %v column to Remap", cp.Name, cp.Remap)
+	}
+	vals := make([]float64, len(cp.Perm))
+	for row := 0; row < dt.Rows; row++ {
+		tsr := dt.CellTensor(cp.Remap, row)
+		if tsr.Len() != len(cp.Perm) {
+			return nil, fmt.Errorf("CurricPhase %v: Perm has %v units but %v has %v", cp.Name, len(cp.Perm), cp.Remap, tsr.Len())
+		}
+		for i, pi := range cp.Perm {
+			vals[i] = tsr.FloatVal1D(pi)
+		}
+		for i, v := range vals {
+			tsr.SetFloat1D(i, v)
+		}
+	}
+	return dt, nil
+}
+
+// Apply sets the layer types and lesions of the phase on the network,
+// and returns the layer types before, for Restore
+func (cp *CurricPhase) Apply(net emer.Network) map[string]emer.LayerType {
+	types := make(map[string]emer.LayerType)
+	for _, lnm := range cp.Inputs {
+		ly := net.LayerByName(lnm)
+		types[lnm] = ly.Type()
+		ly.SetType(emer.Input)
+	}
+	for _, lnm := range cp.Targets {
+		ly := net.LayerByName(lnm)
+		if _, has := types[lnm]; !has {
+			types[lnm] = ly.Type()
+		}
+		ly.SetType(emer.Target)
+	}
+	for _, lnm := range cp.Lesion {
+		net.LayerByName(lnm).SetOff(true)
+	}
+	return types
+}
+
+// Restore un-lesions the layers lesioned by Apply, and sets back the layer types
+func (cp *CurricPhase) Restore(net emer.Network, types map[string]emer.LayerType) {
+	for _, lnm := range cp.Lesion {
+		net.LayerByName(lnm).SetOff(false)
+	}
+	for lnm, typ := range types {
+		net.LayerByName(lnm).SetType(typ)
+	}
+}
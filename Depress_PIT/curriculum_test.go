@@ -0,0 +1,128 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+func TestCurricPhases(t *testing.T) {
+	sch := etable.Schema{
+		{"Training", etensor.STRING, nil, nil},
+		{"Patterns", etensor.STRING, nil, nil},
+		{"MaxEpoch", etensor.INT64, nil, nil},
+		{"Targets", etensor.STRING, nil, nil},
+		{"Mix", etensor.STRING, nil, nil},
+		{"Test", etensor.INT64, nil, nil},
+		{"Remap", etensor.STRING, nil, nil},
+		{"Perm", etensor.STRING, nil, nil},
+	}
+	dt := etable.New(sch, 2)
+	dt.SetCellString("Training", 0, "INSTRUMENTAL")
+	dt.SetCellString("Patterns", 0, "instr.tsv")
+	dt.SetCellFloat("MaxEpoch", 0, 50)
+	dt.SetCellString("Targets", 0, "Approach Behavior")
+	dt.SetCellString("Mix", 0, "M")
+	dt.SetCellString("Training", 1, "REVERSAL")
+	dt.SetCellString("Patterns", 1, "instr.tsv")
+	dt.SetCellFloat("MaxEpoch", 1, 20)
+	dt.SetCellFloat("Test", 1, 1)
+	dt.SetCellString("Remap", 1, "Behavior")
+	dt.SetCellString("Perm", 1, "1 0 2")
+	phases, err := CurricPhases(dt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*CurricPhase{
+		{Name: "INSTRUMENTAL", Patterns: "instr.tsv", MaxEpcs: 50, NZeroStop: -1, Targets: []string{"Approach", "Behavior"}, Mix: "M"},
+		{Name: "REVERSAL", Patterns: "instr.tsv", MaxEpcs: 20, NZeroStop: -1, Targets: []string{}, Test: true, Remap: "Behavior", Perm: []int{1, 0, 2}},
+	}
+	for i := range want {
+		if !reflect.DeepEqual(phases[i], want[i]) {
+			t.Errorf("phase %v: got %+v, want %+v", i, phases[i], want[i])
+		}
+	}
+
+	dt.SetCellString("Perm", 1, "1 x 2")
+	if _, err := CurricPhases(dt); err == nil {
+		t.Errorf("expected an error for an invalid Perm")
+	}
+	dt.SetCellString("Patterns", 0, "")
+	if _, err := CurricPhases(dt); err == nil {
+		t.Errorf("expected an error for a phase with no Patterns")
+	}
+	if _, err := CurricPhases(etable.New(sch[:2], 1)); err == nil {
+		t.Errorf("expected an error for a table with no MaxEpoch column")
+	}
+}
+
+func TestCurricSteps(t *testing.T) {
+	tests := []struct {
+		name  string
+		mixes []string // Mix of each phase
+		want  [][]int  // indexes of the phases of each step
+	}{
+		{"no phases", nil, nil},
+		{"no mixes", []string{"", "", ""}, [][]int{{0}, {1}, {2}}},
+		{"one mix", []string{"M", "M"}, [][]int{{0, 1}}},
+		{"mix between phases", []string{"", "M", "M", "M", ""}, [][]int{{0}, {1, 2, 3}, {4}}},
+		{"two mixes", []string{"A", "A", "B", "B"}, [][]int{{0, 1}, {2, 3}}},
+		{"same mix, not consecutive", []string{"A", "", "A"}, [][]int{{0}, {1}, {2}}},
+	}
+	for _, tt := range tests {
+		phases := make([]*CurricPhase, len(tt.mixes))
+		for i, mx := range tt.mixes {
+			phases[i] = &CurricPhase{Mix: mx}
+		}
+		var got [][]int
+		for _, step := range CurricSteps(phases) {
+			var idxs []int
+			for _, cp := range step {
+				for i := range phases {
+					if phases[i] == cp {
+						idxs = append(idxs, i)
+					}
+				}
+			}
+			got = append(got, idxs)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v: got steps %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCurricPhaseValidate(t *testing.T) {
+	net := &leabra.Network{}
+	net.InitName(net, "Test")
+	net.AddLayer2D("Approach", 1, 4, emer.Target)
+	net.AddLayer2D("Behavior", 1, 3, emer.Target)
+	tests := []struct {
+		name  string
+		cp    CurricPhase
+		valid bool
+	}{
+		{"layers in the network", CurricPhase{Inputs: []string{"Approach"}, Targets: []string{"Behavior"}, Zero: []string{"Approach"}}, true},
+		{"unknown layer", CurricPhase{Lesion: []string{"Hidden3"}}, false},
+		{"test phase in a mix", CurricPhase{Test: true, Mix: "M"}, false},
+		{"remap", CurricPhase{Remap: "Behavior", Perm: []int{2, 0, 1}}, true},
+		{"remap of unknown layer", CurricPhase{Remap: "Motive", Perm: []int{1, 0}}, false},
+		{"remap with no perm", CurricPhase{Remap: "Behavior"}, false},
+		{"perm with no remap", CurricPhase{Perm: []int{1, 0}}, false},
+		{"perm with a repeat", CurricPhase{Remap: "Behavior", Perm: []int{0, 0, 1}}, false},
+		{"perm out of range", CurricPhase{Remap: "Behavior", Perm: []int{0, 1, 3}}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.cp.Validate(net); (err == nil) != tt.valid {
+			t.Errorf("%v: got error %v, want valid: %v", tt.name, err, tt.valid)
+		}
+	}
+}
@@ -28,9 +28,10 @@ import (
 	_ "github.com/emer/emergent/patgen"
 	"github.com/emer/emergent/prjn"
 	"github.com/emer/etable/agg"
+	"github.com/emer/etable/eplot"
 	"github.com/emer/etable/etable"
-	_ "github.com/emer/etable/etensor"
-	_ "github.com/emer/etable/etview" // include to get gui views
+	"github.com/emer/etable/etensor"
+	"github.com/emer/etable/etview"
 	"github.com/emer/etable/split"
 	"github.com/emer/leabra/leabra"
 	"github.com/goki/gi/gi"
@@ -71,9 +72,12 @@ type Sim struct {
 	Params       emer.Params      `view:"inline" desc:"all parameter management"`
 	Instr        *etable.Table     `view:"no-inline" desc:"Training pattern for Instrumental Learning"`
 	Pvlv         *etable.Table     `view:"no-inline" desc:"Training pattern for Pavlovian Learning"`
-	Trn    		 *etable.Table     `view:"no-inline" desc:"Table that controls type of training and number of Epochs of training"`
+	Trn    		 *etable.Table     `view:"no-inline" desc:"Table of the phases of the training curriculum: pattern table, layer types, lesions and number of Epochs of training for each"`
+	PhasePats    map[string]*etable.Table `view:"-" desc:"pattern tables of the curriculum phases, by file name"`
+	Ckpts        Checkpoints      `desc:"weights checkpoints saved at the start of each run and at the end of each phase of the curriculum"`
+	Mix          *CurricMix       `view:"-" desc:"phases of the curriculum whose trials are interleaved in the current training, if any"`
 	TestData 	 *etable.Table     `view:"no-inline" desc:"Table for the Test data file"`
-	Training	 string			   `view:"no-inline" desc:"Type of training: Pavlovian or Instrumental"`
+	Training	 string			   `view:"no-inline" desc:"name of the current phase of training (e.g., PAVLOV or INSTRUMENTAL)"`
 	Tag          string           `desc:"extra tag string to add to any file names output from sim (e.g., weights files, log files, params for run)"`
 	Stats        estats.Stats     `desc:"contains computed statistic values"`
 	LayNms		 []string		  `desc:"Names of Layers to which Training and Testing values are applied"`
@@ -84,6 +88,31 @@ type Sim struct {
 	NZeroStop    int              `desc:"if a positive number, training will stop after this many epochs with zero SSE"`
 	TrainEnv     env.FixedTable   `desc:"Training environment -- contains everything about iterating over input / output patterns over training"`
 	TestEnv      env.FixedTable   `desc:"Testing environment -- manages iterating over testing"`
+	World        *etable.Table    `view:"no-inline" desc:"initial State of the World for the closed-loop simulation"`
+	WorldChanges *etable.Table    `view:"no-inline" desc:"exogenous Changes in the World at each time step"`
+	WorldEffects *etable.Table    `view:"no-inline" desc:"rule table for the effects of each Behavior on EnviroFeatures and InteroState, with its Delay and Cost"`
+	WorldDynamics *etable.Table   `view:"no-inline" desc:"Delay, Decr and Incr for each InteroState unit"`
+	WorldEvents  *etable.Table    `view:"no-inline" desc:"Rate, Val and Dur of each EnviroFeatures event, for generating stochastic WorldChanges"`
+	WorldCircadian *etable.Table  `view:"no-inline" desc:"time-of-day modulation of InteroState drives and gating of EnviroFeatures"`
+	GenTicks     int              `desc:"number of time steps of WorldChanges to generate with GenWorldChanges"`
+	WorldEnv     WorldEnv         `desc:"closed-loop World environment -- feeds the chosen Behavior back into EnviroFeatures and InteroState"`
+	Select       SelectParams     `view:"inline" desc:"policy for selecting a single Behavior from the settled Behavior layer activity"`
+	Stress       StressParams     `view:"inline" desc:"chronic stress accumulator driven by aversive experience, which sets the DyDA input"`
+	TonicDA      TonicDAParams    `view:"inline" desc:"slow adaptation of the tonic VTA drive from the recent history of Reward and DyDA"`
+	RPE          RPEParams        `view:"inline" desc:"phasic dopamine reward prediction error, which gates learning in the Approach pathway"`
+	RT           RTParams         `view:"inline" desc:"settling time (reaction time) of the Behavior layer when testing"`
+	Helpless     HelplessParams   `desc:"learned helplessness protocol: inescapable aversive events in the World, then a test of escape"`
+	HelplessLog  *etable.Table    `view:"no-inline" desc:"escape latency of each episode of the learned helplessness protocol"`
+	Activation   ActivationParams `desc:"behavioral activation therapy: intervention Behaviors promoted on a schedule in the World"`
+	ActivationLog *etable.Table   `view:"no-inline" desc:"mean VTA, DyDA and Approach activity on each day of the World, before, during and after the behavioral activation intervention"`
+	Drugs        DrugSched        `desc:"pharmacological manipulations of projection and layer parameters that ramp in and out over training epochs or World time steps -- open a schedule with OpenDrugs"`
+	Cohort       CohortParams     `desc:"synthetic cohort of individuals with motive biases, VTA baseline and DyDA sensitivity sampled from distributions"`
+	CohortSummary *etable.Table   `view:"no-inline" desc:"traits and outcomes of each individual of the last cohort run"`
+	Symptoms     SymptomParams    `desc:"depression-like symptom scores: anhedonia, avolition, withdrawal and sleep disturbance, and their composite, per epoch or day of the World"`
+	UnitLabels   UnitLabels       `view:"no-inline" desc:"names of the units of each layer, from UnitLabels.tsv or the labels metadata of the pattern table columns -- used in place of unit indexes in the logs"`
+	BehConfusion BehConfusion     `view:"no-inline" desc:"confusion matrix of the target vs. produced Behavior, and the frequency of each Behavior, over the trials of the last test epoch"`
+	PIT          PITParams        `desc:"Pavlovian-Instrumental Transfer test: Pavlovian cues presented in the instrumental context, with Approach and Avoidance left free"`
+	PITEffects   *etable.Table    `view:"no-inline" desc:"PIT effects: shift of each Behavior by each cue over the instrumental baseline, with specific and general transfer"`
 	Time         leabra.Time      `desc:"leabra timing parameters and state"`
 	ViewUpdt     netview.ViewUpdt `view:"inline" desc:"netview update parameters"`
 	TestInterval int              `desc:"how often to run through all the test patterns, in terms of training epochs -- can use 0 or -1 for no testing"`
@@ -96,6 +125,7 @@ type Sim struct {
 	NeedsNewRun  bool             `view:"-" desc:"flag to initialize NewRun if last one finished"`
 	RndSeeds     []int64          `view:"-" desc:"a list of random seeds to use for each run"`
 	NetData      *netview.NetData `view:"-" desc:"net data for recording in nogui mode"`
+	ValsTsrs     map[string]*etensor.Float32 `view:"-" desc:"for holding layer values"`
 }
 
 // this registers this Sim Type and gives it properties that e.g.,
@@ -111,7 +141,30 @@ func (ss *Sim) New() {
 	ss.Instr = &etable.Table{}
 	ss.Pvlv = &etable.Table{}
 	ss.Trn = &etable.Table{}
+	ss.PhasePats = make(map[string]*etable.Table)
 	ss.TestData = &etable.Table{}
+	ss.World = &etable.Table{}
+	ss.WorldChanges = &etable.Table{}
+	ss.WorldEffects = &etable.Table{}
+	ss.WorldDynamics = &etable.Table{}
+	ss.WorldEvents = &etable.Table{}
+	ss.WorldCircadian = &etable.Table{}
+	ss.WorldEnv.Clock.Defaults()
+	ss.Select.Defaults()
+	ss.Stress.Defaults()
+	ss.TonicDA.Defaults()
+	ss.RPE.Defaults()
+	ss.RT.Defaults()
+	ss.Helpless.Defaults()
+	ss.HelplessLog = &etable.Table{}
+	ss.Activation.Defaults()
+	ss.ActivationLog = &etable.Table{}
+	ss.Cohort.Defaults()
+	ss.Symptoms.Defaults()
+	ss.UnitLabels = make(UnitLabels)
+	ss.CohortSummary = &etable.Table{}
+	ss.PIT.Defaults()
+	ss.PITEffects = &etable.Table{}
 	ss.Params.Params = ParamSets
 	ss.Params.AddNetwork(ss.Net)
 	ss.Params.AddSim(ss)
@@ -135,7 +188,13 @@ func (ss *Sim) Config() {
 	ss.OpenPats()
 	ss.ConfigEnv()
 	ss.ConfigNet(ss.Net)
+	if err := ss.WorldEnv.ValidateNet(ss.Net); err != nil {
+		log.Println(err)
+	}
 	ss.ConfigLogs()
+	ss.ConfigBehConfusion()
+	ss.ConfigPITEffects()
+	ss.Activation.ConfigLog(ss.ActivationLog)
 }
 
 func (ss *Sim) ConfigEnv() {
@@ -165,8 +224,23 @@ func (ss *Sim) ConfigEnv() {
 	// ss.TrainEnv.Table = splits.Splits[0]
 	// ss.TestEnv.Table = splits.Splits[1]
 
+	if ss.GenTicks == 0 { // allow user override
+		ss.GenTicks = 24
+	}
+	ss.WorldEnv.Nm = "WorldEnv"
+	ss.WorldEnv.Dsc = "closed-loop World params and state"
+	ss.WorldEnv.World = ss.World
+	ss.WorldEnv.Changes = ss.WorldChanges
+	ss.WorldEnv.Effects = ss.WorldEffects
+	ss.WorldEnv.Dynamics = ss.WorldDynamics
+	ss.WorldEnv.Circadian = ss.WorldCircadian
+	if err := ss.WorldEnv.Validate(); err != nil {
+		log.Println(err)
+	}
+
 	ss.TrainEnv.Init(0)
 	ss.TestEnv.Init(0)
+	ss.WorldEnv.Init(0)
 }
 
 func (ss *Sim) ConfigNet(net *leabra.Network) {
@@ -271,6 +345,13 @@ func (ss *Sim) ConfigNet(net *leabra.Network) {
 		return
 	}
 	net.InitWts()
+
+	for _, dt := range []*etable.Table{ss.Instr, ss.Pvlv, ss.TestData, ss.World} { // labels not in UnitLabels.tsv
+		ss.UnitLabels.SetColMeta(dt)
+	}
+	if err := ss.UnitLabels.Validate(net); err != nil {
+		log.Println(err)
+	}
 }
 
 
@@ -280,6 +361,25 @@ func (ss *Sim) OpenPats() {
 	ss.Trn.OpenCSV("InstrThenPvlv.tsv", etable.Tab) // Order of training and number of epochs for each, 
 	// Pavlov first or Instrumental first. Should eventually create menu to choose.
 	ss.TestData.OpenCSV("DepressPvlv.tsv", etable.Tab) // Test data
+	ss.World.OpenCSV("World.tsv", etable.Tab)               // starting State of the World
+	ss.WorldChanges.OpenCSV("WorldChanges.tsv", etable.Tab) // exogenous Changes per time step
+	ss.WorldEffects.OpenCSV("WorldEffects.tsv", etable.Tab) // effects of each Behavior
+	ss.WorldDynamics.OpenCSV("WorldDynamics.tsv", etable.Tab) // delay, decr, incr for each InteroState
+	ss.WorldEvents.OpenCSV("WorldEvents.tsv", etable.Tab)     // rates for generating WorldChanges
+	ss.WorldCircadian.OpenCSV("WorldCircadian.tsv", etable.Tab) // time-of-day rhythms and opening hours
+	ss.OpenUnitLabels("UnitLabels.tsv")                        // names of the units of each layer
+}
+
+// OpenUnitLabels opens the names of the units of each layer from given table file
+func (ss *Sim) OpenUnitLabels(fnm string) {
+	dt := &etable.Table{}
+	if err := dt.OpenCSV(gi.FileName(fnm), etable.Tab); err != nil {
+		log.Println(err)
+		return
+	}
+	if err := ss.UnitLabels.SetTable(dt); err != nil {
+		log.Println(err)
+	}
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -294,6 +394,15 @@ func (ss *Sim) Init() {
 	ss.GUI.StopNow = false
 	ss.Params.SetMsg = ss.LogSetParams
 	ss.Params.SetAll()
+	if err := ss.Select.Validate(); err != nil {
+		log.Println(err)
+	}
+	ss.Drugs.Reset() // the params are the baselines for the drugs
+	vta := ss.Net.LayerByName("VTA").(leabra.LeabraLayer).AsLeabra()
+	ss.TonicDA.Base = float32(vta.Act.Noise.Mean) // baseline from params, before adaptation
+	if err := ss.Ckpts.Open(); err != nil {
+		log.Println(err)
+	}
 	ss.NewRun()
 	ss.ViewUpdt.Update()
 }
@@ -302,6 +411,7 @@ func (ss *Sim) Init() {
 func (ss *Sim) InitRndSeed() {
 	run := ss.TrainEnv.Run.Cur
 	rand.Seed(ss.RndSeeds[run])
+	ss.Select.Seed(ss.RndSeeds[run])
 }
 
 // NewRndSeed gets a new set of random seeds based on current time -- otherwise uses
@@ -325,13 +435,22 @@ func (ss *Sim) AlphaCyc(train bool) {
 	// ss.Win.PollEvents() // this can be used instead of running in a separate goroutine
 	ss.Net.AlphaCycInit(train)
 	ss.Time.AlphaCycStart()
+	rt := !train && ss.RT.On
+	ss.RT.Init()
+	stop := false // settled, with the rest of the minus phase cycles skipped
 	for qtr := 0; qtr < 4; qtr++ {
-		for cyc := 0; cyc < ss.Time.CycPerQtr; cyc++ {
+		minus := qtr < 3
+		for cyc := 0; cyc < ss.Time.CycPerQtr && !(stop && minus); cyc++ {
 			ss.Net.Cycle(&ss.Time)
 			ss.StatCounters(train)
 			if !train {
 				ss.Log(etime.Test, etime.Cycle)
 			}
+			if rt && minus && ss.RT.Cycle < 0 { // plus phase is driven by the targets
+				beh := ss.ValsTsr("Behavior")
+				ss.Net.LayerByName("Behavior").(leabra.LeabraLayer).AsLeabra().UnitValsTensor(beh, "Act")
+				stop = ss.RT.Update(beh.Values, ss.Time.Cycle) && ss.RT.Stop
+			}
 			ss.Time.CycleInc()
 			ss.ViewUpdt.UpdateCycle(cyc)
 		}
@@ -339,10 +458,17 @@ func (ss *Sim) AlphaCyc(train bool) {
 		ss.Time.QuarterInc()
 		ss.ViewUpdt.UpdateTime(etime.GammaCycle)
 	}
+	if rt {
+		ss.Stats.SetInt("RT", ss.RT.RT(3*ss.Time.CycPerQtr))
+	}
 	ss.StatCounters(train)
+	ss.SelectBehavior()
+	ss.ComputeRPE()
 
 	if train {
+		ss.ApplyRPE(ss.RPE.LrateMod()) // 1 if not On
 		ss.Net.DWt()
+		ss.ApplyRPE(1) // not left scaled, e.g., when the gating is turned off
 		ss.ViewUpdt.RecordSyns() // note: critical to update weights here so DWt is visible
 		ss.Net.WtFmDWt()
 	}
@@ -357,19 +483,32 @@ func (ss *Sim) AlphaCyc(train bool) {
 // args so that it can be used for various different contexts
 // (training, testing, etc).
 func (ss *Sim) ApplyInputs(en env.Env) {
-	// ss.Net.InitExt() // clear any existing inputs -- not strictly necessary if always
-	// going to the same layers, but good practice and cheap anyway
+	ss.Net.InitExt() // clear any existing inputs -- needed because the WorldEnv
+	// leaves Approach, Avoidance and Behavior free
 
 	lays := ss.LayNms
 	for _, lnm := range lays {
 		ly := ss.Net.LayerByName(lnm).(leabra.LeabraLayer).AsLeabra()
 		pats := en.State(ly.Nm)
+		if lnm == "DyDA" && ss.Stress.On { // set from experience, not the patterns
+			pats = ss.StressDyDA(ly)
+		}
 		if pats != nil {
 			ly.ApplyExt(pats)
 		}
 	}
 }
 
+// StressDyDA returns the DyDA input pattern for the current Stress level
+func (ss *Sim) StressDyDA(ly *leabra.Layer) etensor.Tensor {
+	dy := ss.ValsTsr("DyDA")
+	dy.SetShape(ly.Shp.Shp, nil, nil)
+	for i := range dy.Values {
+		dy.Values[i] = ss.Stress.DyDA()
+	}
+	return dy
+}
+
 // TrainTrial runs one trial of training using TrainEnv
 func (ss *Sim) TrainTrial() {
 	if ss.NeedsNewRun {
@@ -377,6 +516,9 @@ func (ss *Sim) TrainTrial() {
 	}
 
 	ss.TrainEnv.Step() // the Env encapsulates and manages all counter state
+	if len(ss.Drugs.Drugs) > 0 {
+		ss.Stats.SetFloat("DrugResp", ss.Drugs.Apply(ss.Net, "Epoch", float64(ss.TrainEnv.Epoch.Cur)))
+	}
 
 	// Key to query counters FIRST because current state is in NEXT epoch
 	// if epoch counter has changed
@@ -401,11 +543,20 @@ func (ss *Sim) TrainTrial() {
 				return
 			}
 		}
+		if ss.Mix != nil { // fresh subsample of the patterns of the phases for the new epoch
+			ss.Mix.Sample()
+		}
 	}
 
+	if ss.Mix != nil { // interleaved phases: layer types and lesions of the phase of this trial
+		ss.Mix.SetTrial(ss.Net, ss.TrainEnv.Row())
+	}
 	ss.ApplyInputs(&ss.TrainEnv)
 	ss.AlphaCyc(true) // train
 	ss.TrialStats()
+	ss.UpdateStress()
+	ss.UpdateTonicDA()
+	ss.UpdateSymptoms(&ss.Symptoms.Train, -1)
 	ss.Log(etime.Train, etime.Trial)
 	if (ss.PCAInterval > 0) && (epc%ss.PCAInterval == 0) {
 		ss.Log(etime.Analyze, etime.Trial)
@@ -414,6 +565,7 @@ func (ss *Sim) TrainTrial() {
 
 // RunEnd is called at the end of a run -- save weights, record final log, etc here
 func (ss *Sim) RunEnd() {
+	ss.Stats.SetInt("TrlsToCrit", ss.TrlsToCrit())
 	ss.Log(etime.Train, etime.Run)
 	if ss.SaveWts {
 		fnm := ss.WeightsFileName()
@@ -422,6 +574,17 @@ func (ss *Sim) RunEnd() {
 	}
 }
 
+// TrlsToCrit returns the number of training trials it took to reach the
+// criterion: the trials of the epochs before FirstZero, the first epoch with
+// zero errors of the run (or phase), or -1 if it was not reached
+func (ss *Sim) TrlsToCrit() int {
+	fz := ss.Stats.Int("FirstZero")
+	if fz < 0 {
+		return -1
+	}
+	return fz * ss.TrainEnv.Trial.Max // trials actually run per epoch
+}
+
 // NewRun intializes a new run of the model, using the TrainEnv.Run counter
 // for the new run value
 func (ss *Sim) NewRun() {
@@ -429,9 +592,16 @@ func (ss *Sim) NewRun() {
 	run := ss.TrainEnv.Run.Cur
 	ss.TrainEnv.Init(run)
 	ss.TestEnv.Init(run)
+	ss.WorldEnv.Init(run)
+	ss.Stress.Init()
+	ss.TonicDA.Init()
+	ss.ApplyTonicDA()
 	ss.Time.Reset()
-	ss.Net.SaveWtsJSON("trained.wts")
+	ss.Drugs.Restore(ss.Net)
 	ss.Net.InitWts()
+	if ss.Ckpts.Start == "" { // not needed when resuming from a later checkpoint
+		ss.SaveCheckpoint(run, 0, InitPhase)
+	}
 	ss.InitStats()
 	ss.StatCounters(true)
 	ss.Logs.ResetLog(etime.Train, etime.Epoch)
@@ -493,138 +663,167 @@ func (ss *Sim) SaveWeights(filename gi.FileName) {
 	ss.Net.SaveWtsJSON(filename)
 }
 
+// TrainPIT trains the network on all the phases of the curriculum in the Trn
+// table in order, with the phases of a Mix interleaved.  The first phase starts
+// from the Ckpts.Start checkpoint, or else the INIT checkpoint of the run, and
+// each next phase from the checkpoint saved at the end of the previous one,
+// unless it has its own Start.
 func (ss *Sim) TrainPIT() {
+	phases, err := CurricPhases(ss.Trn)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	for _, ph := range phases {
+		if err := ph.Validate(ss.Net); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+	run := ss.TrainEnv.Run.Cur
+	prv := ss.Ckpts.Start
+	if prv == "" {
+		prv = ss.Ckpts.Latest(run, InitPhase)
+	}
+	for _, step := range CurricSteps(phases) {
+		ph := step[0]
+		start := prv
+		if ph.Start != "" {
+			start = ss.Ckpts.Find(run, ph.Start)
+		}
+		if len(step) > 1 {
+			prv, err = ss.TrainMix(step, run, start)
+		} else {
+			prv, err = ss.TrainPhase(ph, run, start)
+		}
+		if err != nil {
+			log.Println(err)
+			return
+		}
+	}
+}
 
-for i := 0; i < 2; i++ {
-		
-ss.Training = ss.Trn.CellString("Training", i)
-ss.MaxEpcs = int(ss.Trn.CellFloat("MaxEpoch", i))
-
-
-
-switch ss.Training {
-
-	case "INSTRUMENTAL":
-
-	ss.TrainEnv.Epoch.Cur = 0  //set current epoch to 0 so that training starts from 0 epochs
-	
-	// Need to set up training patterns.  	
-	ss.TrainEnv.Table = etable.NewIdxView(ss.Instr)
-	ss.TestEnv.Table = etable.NewIdxView(ss.Instr)
-	
-	run := ss.TrainEnv.Run.Cur  //  Is Init what is resetting the TrainEnv table index properly?
+// TrainPhase trains the network on one phase of the curriculum, with its own
+// patterns, layer types, lesions and stopping criteria, starting from the
+// start checkpoint (current weights if empty).  Returns the checkpoint
+// saved at the end of the phase.
+func (ss *Sim) TrainPhase(ph *CurricPhase, run int, start string) (string, error) {
+	pats, err := ss.PhasePatterns(ph.Patterns)
+	if err != nil {
+		return "", err
+	}
+	pats, err = ph.PhasePats(pats)
+	if err != nil {
+		return "", err
+	}
+	ss.Training = ph.Name
+	ss.Stats.SetString("Phase", ph.Name)
+	ss.MaxEpcs = ph.MaxEpcs
+	ss.NZeroStop = ph.NZeroStop
+
+	ss.TrainEnv.Epoch.Cur = 0 //set current epoch to 0 so that training starts from 0 epochs
+	ss.TrainEnv.Table = etable.NewIdxView(pats)
+	ss.TestEnv.Table = etable.NewIdxView(pats)
 	ss.TrainEnv.Init(run)
 
-// Unlesion Hidden and Behavior layer to make sure all layers are unlesioned
-	ss.Net.LayerByName("Hidden2").SetOff(false)
-	ss.Net.LayerByName("Behavior").SetOff(false)
-
-// Load saved weights
-// OpenWtsJSON opens trained weights
-		ss.Net.OpenWtsJSON("trained.wts")
-	
-
-// Define Environment and InteroState as Compare layers
-// Define Approach and Avoid as Input layers
-// Define Behavior as a Target layer
-	ss.Net.LayerByName("EnviroFeatures").SetType(emer.Input)
-	ss.Net.LayerByName("InteroState").SetType(emer.Input)
-	ss.Net.LayerByName("Approach").SetType(emer.Input)
-	ss.Net.LayerByName("Avoidance").SetType(emer.Input)
-	ss.Net.LayerByName("Behavior").SetType(emer.Target)
-	
-
-// Lesion Environment, InteroState, and Hidden2 layers
-	ss.Net.LayerByName("EnviroFeatures").SetOff(true)
-	ss.Net.LayerByName("InteroState").SetOff(true)
-	ss.Net.LayerByName("Hidden1").SetOff(true)
-
-	ss.Train()
-
-
-// Unlesion Environment and InteroState layers
-	ss.Net.LayerByName("EnviroFeatures").SetOff(false)
-	ss.Net.LayerByName("InteroState").SetOff(false)
-	ss.Net.LayerByName("Hidden1").SetOff(false)
-
-// Save weights
-	ss.Net.SaveWtsJSON("trained.wts")
-
-
-
-// Define Environment and InteroState as Input layers
-// Define Approach and Avoid as Target layers
-// Define Behavior as a Target layer
-// Makes sure that layers are set to default.
-	ss.Net.LayerByName("EnviroFeatures").SetType(emer.Input)
-	ss.Net.LayerByName("InteroState").SetType(emer.Input)
-	ss.Net.LayerByName("Approach").SetType(emer.Target)
-	ss.Net.LayerByName("Avoidance").SetType(emer.Target)	
-	ss.Net.LayerByName("Behavior").SetType(emer.Target)
-
-	case "PAVLOV":
-
-	ss.TrainEnv.Epoch.Cur = 0  //set current epoch to 0 so that training starts from 0 epochs
-	
-// Pavlovian Training
+	if start != "" {
+		if err := ss.Net.OpenWtsJSON(gi.FileName(start)); err != nil {
+			return "", err
+		}
+	}
+	types := ph.Apply(ss.Net)
+	if ph.Test {
+		ss.TestPhase(ph)
+		ph.Restore(ss.Net, types)
+		return start, nil // no learning, so no new checkpoint
+	}
+	ss.TrainRunPhase(run)
+	ph.Restore(ss.Net, types)
+	return ss.SaveCheckpoint(run, ss.TrainEnv.Epoch.Cur, ph.Name), nil
+}
 
-// Need to set up training patterns  
+// TestPhase runs MaxEpoch epochs (at least one) of testing trials on the
+// patterns of a test phase of the curriculum, without learning
+func (ss *Sim) TestPhase(ph *CurricPhase) {
+	ss.GUI.StopNow = false
+	nepc := ph.MaxEpcs
+	if nepc < 1 {
+		nepc = 1
+	}
+	for epc := 0; epc < nepc && !ss.GUI.StopNow; epc++ {
+		ss.TestAll()
+	}
+	ss.Stopped()
+}
 
-	ss.TrainEnv.Table = etable.NewIdxView(ss.Pvlv)
-	ss.TestEnv.Table = etable.NewIdxView(ss.Pvlv)	
-	
-	run := ss.TrainEnv.Run.Cur  //  Is Init what is resetting the TrainEnv table index properly?
+// TrainMix trains the network on the phases of a Mix of the curriculum, with
+// their trials interleaved within each epoch, and the layer types and lesions
+// switched to those of the phase of each trial.  MaxEpoch, NZeroStop and Start
+// are those of the first phase.  Returns the checkpoint saved at the end.
+func (ss *Sim) TrainMix(phases []*CurricPhase, run int, start string) (string, error) {
+	pats := make([]*etable.Table, len(phases))
+	for i, ph := range phases {
+		pt, err := ss.PhasePatterns(ph.Patterns)
+		if err != nil {
+			return "", err
+		}
+		pats[i], err = ph.PhasePats(pt)
+		if err != nil {
+			return "", err
+		}
+	}
+	mix, err := NewCurricMix(phases, pats, ss.RndSeeds[run])
+	if err != nil {
+		return "", err
+	}
+	ss.Training = mix.Name
+	ss.Stats.SetString("Phase", mix.Name)
+	ss.MaxEpcs = phases[0].MaxEpcs
+	ss.NZeroStop = phases[0].NZeroStop
+
+	ss.TrainEnv.Epoch.Cur = 0
+	ss.TrainEnv.Table = etable.NewIdxView(mix.Table)
+	ss.TestEnv.Table = etable.NewIdxView(mix.Table)
 	ss.TrainEnv.Init(run)
 
-	
-
-
-
-// Define Environment and InteroState as Input layers
-// Define Approach and Avoid as Target layers
-// Define Behavior as a Compare layer
-	ss.Net.LayerByName("EnviroFeatures").SetType(emer.Input)
-	ss.Net.LayerByName("InteroState").SetType(emer.Input)
-	ss.Net.LayerByName("Approach").SetType(emer.Target)
-	ss.Net.LayerByName("Avoidance").SetType(emer.Target)
-	ss.Net.LayerByName("Behavior").SetType(emer.Target)
-
-// Load saved weights
-		ss.Net.OpenWtsJSON("trained.wts")
-	
-
-// Lesion Hidden layer and Behavior layer
-	ss.Net.LayerByName("Hidden2").SetOff(true)
-	ss.Net.LayerByName("Behavior").SetOff(true)
-
+	if start != "" {
+		if err := ss.Net.OpenWtsJSON(gi.FileName(start)); err != nil {
+			return "", err
+		}
+	}
+	ss.Mix = mix
+	ss.TrainRunPhase(run)
+	mix.Restore(ss.Net)
+	ss.Mix = nil
+	return ss.SaveCheckpoint(run, ss.TrainEnv.Epoch.Cur, mix.Name), nil
+}
 
-// Train until number of Epochs of training reached
+// TrainRunPhase trains the current phase of the curriculum until its stop
+// criteria, within the given run only -- the next phase continues the run,
+// with its own FirstZero and trials to criterion
+func (ss *Sim) TrainRunPhase(run int) {
+	ss.Stats.SetInt("FirstZero", -1)
+	ss.Stats.SetInt("NZero", 0)
+	mx := ss.TrainEnv.Run.Max
+	ss.TrainEnv.Run.Max = run + 1 // stop at the end of the phase
+	ss.NeedsNewRun = false
 	ss.Train()
+	ss.TrainEnv.Run.Max = mx
+	ss.TrainEnv.Run.Set(run) // Train wraps the run counter at the end
+}
 
-//Unlesion Hidden Layer and Behavior layer
-	ss.Net.LayerByName("Hidden2").SetOff(false)
-	ss.Net.LayerByName("Behavior").SetOff(false)
-
-
-// Save weights
-	ss.Net.SaveWtsJSON("trained.wts")
-	
-	
-// Define Environment and InteroState as Input layers
-// Define Approach and Avoid as Target layers
-// Define Behavior as a Target layer
-// Makes sure that layers are set to default.
-
-	ss.Net.LayerByName("EnviroFeatures").SetType(emer.Input)
-	ss.Net.LayerByName("InteroState").SetType(emer.Input)
-	ss.Net.LayerByName("Approach").SetType(emer.Target)
-	ss.Net.LayerByName("Avoidance").SetType(emer.Target)	
-	ss.Net.LayerByName("Behavior").SetType(emer.Target)
-
-	
-	
-		}
+// PhasePatterns returns the pattern table of the given file name,
+// opening it the first time it is used by a phase of the curriculum
+func (ss *Sim) PhasePatterns(fnm string) (*etable.Table, error) {
+	if dt, has := ss.PhasePats[fnm]; has {
+		return dt, nil
+	}
+	dt := &etable.Table{}
+	if err := dt.OpenCSV(gi.FileName(fnm), etable.Tab); err != nil {
+		return nil, err
 	}
+	ss.PhasePats[fnm] = dt
+	return dt, nil
 }
 
 
@@ -655,6 +854,7 @@ func (ss *Sim) TestTrial(returnOnChg bool) {
 	ss.ApplyInputs(&ss.TestEnv)
 	ss.AlphaCyc(false) // !train
 	ss.TrialStats()
+	ss.UpdateSymptoms(&ss.Symptoms.Test, -1)
 	ss.Log(etime.Test, etime.Trial)
 	if ss.NetData != nil { // offline record net data from testing, just final state
 		ss.NetData.Record(ss.ViewUpdt.Text, -1, 1)
@@ -690,6 +890,404 @@ func (ss *Sim) RunTestAll() {
 	ss.TestAll()
 	ss.Stopped()
 }
+
+////////////////////////////////////////////////////////////////////////////////////////////
+// World
+
+// WorldStep runs one time step of the closed-loop World: the current State of the
+// World is presented, the network settles without learning, and the Behavior it
+// chooses is fed back to the WorldEnv to change the State for the next step.
+func (ss *Sim) WorldStep() {
+	ss.WorldEnv.Step()
+	if ss.Helpless.On { // aversive event of the helplessness protocol is held on
+		ss.Helpless.SetEvent(&ss.WorldEnv, true)
+	}
+	if ss.Activation.On {
+		ss.Activation.Schedule(&ss.WorldEnv)
+	}
+	if len(ss.Drugs.Drugs) > 0 {
+		t := ss.WorldEnv.Epoch.Cur*ss.WorldEnv.Tick.Max + ss.WorldEnv.Tick.Cur
+		ss.Stats.SetFloat("DrugResp", ss.Drugs.Apply(ss.Net, "Tick", float64(t)))
+	}
+	if ss.WorldEnv.Tick.Cur == 0 { // new day -- rows are logged by Tick
+		ss.Logs.ResetLog(etime.Test, etime.Tick)
+		ss.Symptoms.Day.Reset()
+	}
+	ss.Stats.SetInt("Day", ss.WorldEnv.Epoch.Cur)
+	ss.Stats.SetInt("Tick", ss.WorldEnv.Tick.Cur)
+	ss.Stats.SetString("TickName", ss.WorldEnv.TickName.Cur)
+	ss.Stats.SetFloat("Hour", float64(ss.WorldEnv.Clock.Hour))
+
+	ss.ApplyInputs(&ss.WorldEnv)
+	ss.AlphaCyc(false) // !train
+	ss.TrialStats()
+	ss.UpdateStress()
+	ss.UpdateTonicDA()
+	ss.UpdateSymptoms(&ss.Symptoms.Day, ss.WorldEnv.Clock.Hour)
+	ss.Symptoms.SetStats(&ss.Stats, &ss.Symptoms.Day) // running scores for the day so far
+
+	chs := ss.ValsTsr("BehChoice") // one-hot pattern for the chosen Behavior
+	chs.CopyShapeFrom(ss.ValsTsr("Behavior"))
+	chs.SetZeros()
+	if beh := ss.Stats.Int("ChosenBeh"); beh >= 0 {
+		chs.Values[beh] = 1
+	}
+	ss.WorldEnv.Action("Behavior", chs)
+	if ss.Activation.On {
+		vta := ss.Net.LayerByName("VTA").(leabra.LeabraLayer).AsLeabra()
+		dyda := ss.Net.LayerByName("DyDA").(leabra.LeabraLayer).AsLeabra()
+		ss.Activation.Accum(ss.ActivationLog, ss.Params.Name(), ss.WorldEnv.Epoch.Cur, ss.Stats.Int("ChosenBeh"), vta.Pools[0].ActM.Avg, dyda.Pools[0].ActM.Avg, float32(ss.Stats.Float("ApproachAct")), ss.Stress.Stress)
+		ss.Activation.Cur = -1 // only applies to the selection on this time step
+	}
+	ss.Log(etime.Test, etime.Tick)
+}
+
+// SimulateDays loads trained weights from given file, which is required, and
+// runs the closed-loop World for given number of days (passes through the
+// WorldChanges), saving one row per time step to the timeline log file
+func (ss *Sim) SimulateDays(days int, wtsFile string) error {
+	if wtsFile == "" {
+		return fmt.Errorf("SimulateDays: no trained weights file to load")
+	}
+	if err := ss.Net.OpenWtsJSON(gi.FileName(wtsFile)); err != nil {
+		return fmt.Errorf("SimulateDays: could not load trained weights: %v", err)
+	}
+	ss.WorldEnv.Init(ss.TrainEnv.Run.Cur)
+	ss.Activation.ConfigLog(ss.ActivationLog)
+	nticks := days * ss.WorldEnv.Tick.Max
+	if nticks <= 0 {
+		return fmt.Errorf("SimulateDays: no time steps to run -- days: %v, WorldChanges rows: %v", days, ss.WorldEnv.Tick.Max)
+	}
+	fnm := ss.LogFileName("timeline")
+	ss.Logs.SetLogFile(etime.Test, etime.Tick, fnm)
+	fmt.Printf("Simulating %d days of %d time steps, saving timeline to: %s\n", days, ss.WorldEnv.Tick.Max, fnm)
+	for i := 0; i < nticks; i++ {
+		ss.WorldStep()
+	}
+	ss.Activation.LogDay(ss.ActivationLog, ss.Params.Name())
+	ss.Drugs.Restore(ss.Net)
+	return nil
+}
+
+// GenWorldChanges replaces the WorldChanges with GenTicks time steps of stochastic
+// events sampled from the WorldEvents table, using the random seed for the current
+// run, and saves them so that the run can be replayed by opening that file.
+func (ss *Sim) GenWorldChanges() {
+	run := ss.TrainEnv.Run.Cur
+	start := ss.World.CellTensor("EnviroFeatures", 0)
+	err := GenWorldChanges(ss.WorldChanges, ss.WorldEvents, "EnviroFeatures", ss.GenTicks, ss.RndSeeds[run], start)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	fnm := ss.LogFileName(fmt.Sprintf("WorldChanges_%03d", run))
+	ss.WorldChanges.SaveCSV(gi.FileName(fnm), etable.Tab, etable.Headers)
+	if err := ss.WorldEnv.Validate(); err != nil {
+		log.Println(err)
+	}
+	ss.WorldEnv.Init(run)
+}
+
+// PITTrial runs one testing trial of the PIT test, with the instrumental
+// context inputs, and the cue in the given row of the patterns on the Cues
+// layers, or no cue for the baseline if pats is nil, and returns the Behavior
+// activity (ActM)
+func (ss *Sim) PITTrial(ctx map[string]*etensor.Float32, pats *etable.Table, row int) []float32 {
+	ss.Net.InitExt() // all other layers are left free
+	for lnm, pat := range ctx {
+		if lnm == "DyDA" && ss.Stress.On { // set from experience, not the patterns
+			continue
+		}
+		ss.Net.LayerByName(lnm).(leabra.LeabraLayer).AsLeabra().ApplyExt(pat)
+	}
+	if ss.Stress.On {
+		ly := ss.Net.LayerByName("DyDA").(leabra.LeabraLayer).AsLeabra()
+		ly.ApplyExt(ss.StressDyDA(ly))
+	}
+	if pats != nil {
+		for _, lnm := range ss.PIT.Cues {
+			ly := ss.Net.LayerByName(lnm).(leabra.LeabraLayer).AsLeabra()
+			ly.ApplyExt(pats.CellTensor(lnm, row))
+		}
+	}
+	ss.AlphaCyc(false) // !train
+	out := ss.Net.LayerByName("Behavior").(leabra.LeabraLayer).AsLeabra()
+	acts := ss.ValsTsr("Behavior")
+	out.UnitValsTensor(acts, "ActM")
+	return append([]float32{}, acts.Values...)
+}
+
+// PITTest runs the Pavlovian-Instrumental Transfer test: the baseline with no
+// cue, then each cue in the PIT Patterns, all in the same instrumental context,
+// recording the shift of each Behavior over the baseline, and the specific and
+// general transfer, in PITEffects
+func (ss *Sim) PITTest() {
+	pats, err := ss.PhasePatterns(ss.PIT.Patterns)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	instr, err := ss.PhasePatterns(ss.PIT.Instr)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	ctx, err := ss.PIT.Context(instr)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	for _, lnm := range ss.PIT.Cues {
+		if pats.ColByName(lnm) == nil {
+			log.Printf("PITTest: patterns %v have no cue layer %v\n", ss.PIT.Patterns, lnm)
+			return
+		}
+	}
+	ss.PIT.Base = ss.PITTrial(ctx, nil, 0)
+	nbeh := len(ss.PIT.Base)
+	dt := ss.PITEffects
+	ss.PIT.ConfigEffects(dt, nbeh)
+	dt.SetNumRows(pats.Rows + 1)
+	dt.SetCellFloat("Cue", 0, -1)
+	dt.SetCellString("CueName", 0, "Baseline")
+	dt.SetCellFloat("Motive", 0, -1)
+	dt.SetCellTensor("Act", 0, etensor.NewFloat32Shape(etensor.NewShape([]int{1, nbeh}, nil, nil), ss.PIT.Base))
+	effects := make([]float32, nbeh)
+	for row := 0; row < pats.Rows; row++ {
+		if ss.GUI.StopNow {
+			break
+		}
+		acts := ss.PITTrial(ctx, pats, row)
+		for b := range effects {
+			effects[b] = acts[b] - ss.PIT.Base[b]
+		}
+		mot := ss.PIT.CueMotive(pats, row)
+		spec, gen := ss.PIT.Transfer(effects, mot)
+		nm := ""
+		if pats.ColByName("Name") != nil {
+			nm = pats.CellString("Name", row)
+		}
+		if nm == "" {
+			nm = fmt.Sprintf("Cue%03d", row)
+		}
+		dr := row + 1
+		dt.SetCellFloat("Cue", dr, float64(row))
+		dt.SetCellString("CueName", dr, nm)
+		dt.SetCellFloat("Motive", dr, float64(mot))
+		dt.SetCellFloat("Specific", dr, float64(spec))
+		dt.SetCellFloat("General", dr, float64(gen))
+		act := dt.CellTensor("Act", dr).(*etensor.Float32)
+		copy(act.Values, acts)
+		eff := dt.CellTensor("Effect", dr).(*etensor.Float32)
+		copy(eff.Values, effects)
+	}
+	ss.UpdatePITViews()
+}
+
+// ConfigPITEffects configures the table of the PIT effects, in MiscTables as PITEffects
+func (ss *Sim) ConfigPITEffects() {
+	ss.PIT.ConfigEffects(ss.PITEffects, ss.Net.LayerByName("Behavior").Shape().Len())
+	ss.Logs.MiscTables["PITEffects"] = ss.PITEffects
+}
+
+// UpdatePITViews updates the PITEffects table and plot in the GUI, if any
+func (ss *Sim) UpdatePITViews() {
+	if ss.GUI.TabView == nil {
+		return
+	}
+	if tab, err := ss.GUI.TabView.TabByNameTry("PITEffects"); err == nil {
+		tab.(*etview.TableView).SetTable(ss.PITEffects, nil)
+	}
+	if tab, err := ss.GUI.TabView.TabByNameTry("PITPlot"); err == nil {
+		plt := tab.(*eplot.Plot2D)
+		plt.SetTable(ss.PITEffects)
+		plt.GoUpdate()
+	}
+}
+
+// RunPITTest runs the PIT test from the toolbar
+func (ss *Sim) RunPITTest() {
+	ss.GUI.StopNow = false
+	ss.PITTest()
+	ss.Stopped()
+}
+
+// Helplessness runs the learned helplessness protocol in the closed-loop World,
+// from its current State: YokedEps inescapable aversive events, then TestEps
+// events that end when an Escape Behavior is chosen.  Each episode is logged
+// in HelplessLog, and the mean escape Latency and learning Rate are set in Helpless.
+func (ss *Sim) Helplessness() {
+	hp := &ss.Helpless
+	hp.ConfigLog(ss.HelplessLog)
+	phases := []struct {
+		nm   string
+		neps int
+	}{{"Yoked", hp.YokedEps}, {"Test", hp.TestEps}}
+	for _, ph := range phases {
+		for ep := 0; ep < ph.neps && !ss.GUI.StopNow; ep++ {
+			for i := 0; i < hp.ITI; i++ {
+				ss.WorldStep()
+			}
+			hp.SetEvent(&ss.WorldEnv, true)
+			lat, escaped := hp.MaxLat, false
+			for t := 0; t < hp.MaxLat; t++ {
+				ss.WorldStep()
+				if beh := ss.Stats.Int("ChosenBeh"); ph.nm == "Test" && hp.IsEscape(beh) {
+					lat, escaped = t+1, true
+					if hp.Learn {
+						ss.EscapeTrial(beh)
+					}
+					break
+				}
+			}
+			hp.SetEvent(&ss.WorldEnv, false)
+			hp.LogEpisode(ss.HelplessLog, ph.nm, ep, lat, escaped, ss.Stress.Stress)
+		}
+	}
+	hp.Summary(ss.HelplessLog)
+}
+
+// EscapeTrial reinforces an escape with a training trial on the current State
+// of the World, with the chosen Behavior as the target
+func (ss *Sim) EscapeTrial(beh int) {
+	ss.ApplyInputs(&ss.WorldEnv)
+	chs := ss.ValsTsr("BehChoice")
+	chs.CopyShapeFrom(ss.ValsTsr("Behavior"))
+	chs.SetZeros()
+	chs.Values[beh] = 1
+	ss.Net.LayerByName("Behavior").(leabra.LeabraLayer).AsLeabra().ApplyExt(chs)
+	ss.AlphaCyc(true)
+}
+
+// RunHelplessness runs the learned helplessness protocol from the GUI
+func (ss *Sim) RunHelplessness() {
+	ss.GUI.StopNow = false
+	ss.Helplessness()
+	ss.Stopped()
+}
+
+// OpenDrugs opens a schedule of pharmacological manipulations from the given
+// drug table (e.g., Drugs.tsv), which is applied in training and in the World
+func (ss *Sim) OpenDrugs(fnm gi.FileName) error {
+	dt := &etable.Table{}
+	if err := dt.OpenCSV(fnm, etable.Tab); err != nil {
+		return err
+	}
+	ss.Drugs.Restore(ss.Net)
+	if err := ss.Drugs.SetTable(dt); err != nil {
+		return err
+	}
+	return ss.Drugs.Validate(ss.Net)
+}
+
+// RunCohort samples the individuals of the Cohort and runs each one through
+// training and testing, from the initial weights of the current run, and then
+// Cohort.Days days of the closed-loop World, with one row per individual in
+// CohortSummary.  The motive biases of the patterns, the VTA baseline and the
+// DyDA sensitivity are set back at the end.
+func (ss *Sim) RunCohort() {
+	cp := &ss.Cohort
+	nApp := ss.Net.LayerByName("MBApp").Shape().Len()
+	nAv := ss.Net.LayerByName("MBAv").Shape().Len()
+	cp.ConfigSummary(ss.CohortSummary, nApp, nAv)
+	tbls := ss.CohortTables()
+	orig := SaveBiases(tbls)
+	vtaBase, dydaGain := ss.TonicDA.Base, ss.Stress.DyDAGain
+	run := ss.TrainEnv.Run.Cur
+	for _, ind := range cp.Sample(nApp, nAv) {
+		for _, dt := range tbls {
+			ind.SetBiases(dt)
+		}
+		ss.TonicDA.Base = ind.VTABase
+		ss.Stress.DyDAGain = ind.DyDAGain
+		ss.TrainEnv.Run.Set(run) // NewRun seeds and inits from the run counter
+		ss.NewRun()
+		ss.TrainIndividual(run)
+		row := ind.AddSummary(ss.CohortSummary)
+		ss.CohortOutcomes(row)
+		fmt.Printf("Cohort individual: %d\tEpochs: %d\tWorldStress: %.3g\n", ind.ID, ss.TrainEnv.Epoch.Cur, ss.CohortSummary.CellFloat("WorldStress", row))
+	}
+	RestoreBiases(orig)
+	ss.TonicDA.Base, ss.Stress.DyDAGain = vtaBase, dydaGain
+	ss.NewRun()
+}
+
+// CohortOutcomes sets the outcomes of the trained network in the given row of
+// CohortSummary: training, then a test of all the patterns, then the mean
+// Stress, VTA and Approach activity over Cohort.Days days of the World,
+// starting from no Stress
+func (ss *Sim) CohortOutcomes(row int) {
+	dt := ss.CohortSummary
+	dt.SetCellFloat("Epochs", row, float64(ss.TrainEnv.Epoch.Cur))
+	dt.SetCellFloat("TrlsToCrit", row, float64(ss.TrlsToCrit()))
+	ss.GUI.StopNow = false // set at the end of training
+	ss.TestAll()
+	tst := ss.Logs.Table(etime.Test, etime.Epoch)
+	if tst.Rows > 0 {
+		dt.SetCellFloat("TestPctErr", row, tst.CellFloat("PctErr", tst.Rows-1))
+		dt.SetCellFloat("TestApproach", row, tst.CellFloat("ApproachAct", tst.Rows-1))
+		dt.SetCellFloat("TestAvoid", row, tst.CellFloat("AvoidAct", tst.Rows-1))
+	}
+	ss.WorldEnv.Init(ss.TrainEnv.Run.Cur)
+	ss.Stress.Init() // the World outcomes start from no Stress, as in training
+	vta := ss.Net.LayerByName("VTA").(leabra.LeabraLayer).AsLeabra()
+	nticks := ss.Cohort.Days * ss.WorldEnv.Tick.Max
+	var stress, vact, appr float64
+	for i := 0; i < nticks; i++ {
+		ss.WorldStep()
+		stress += float64(ss.Stress.Stress)
+		vact += float64(vta.Pools[0].ActM.Avg)
+		appr += ss.Stats.Float("ApproachAct")
+	}
+	if nticks > 0 {
+		n := float64(nticks)
+		dt.SetCellFloat("WorldStress", row, stress/n)
+		dt.SetCellFloat("WorldVTA", row, vact/n)
+		dt.SetCellFloat("WorldApproach", row, appr/n)
+	}
+	dt.SetCellFloat("WorldTonicDA", row, float64(ss.TonicDA.Drive))
+}
+
+// RunCohortGUI runs the Cohort from the GUI
+func (ss *Sim) RunCohortGUI() {
+	ss.GUI.StopNow = false
+	ss.RunCohort()
+	ss.Stopped()
+}
+
+// CohortTables returns the pattern tables whose motive biases are set for
+// each individual of the cohort: those of the curriculum phases, the other
+// training and test patterns, and the World
+func (ss *Sim) CohortTables() []*etable.Table {
+	tbls := []*etable.Table{ss.Instr, ss.Pvlv, ss.TestData, ss.World}
+	if phases, err := CurricPhases(ss.Trn); err == nil {
+		for _, ph := range phases {
+			if dt, err := ss.PhasePatterns(ph.Patterns); err == nil {
+				tbls = append(tbls, dt)
+			}
+		}
+	}
+	return tbls
+}
+
+// TrainIndividual trains an individual of the cohort through the curriculum
+func (ss *Sim) TrainIndividual(run int) {
+	ss.TrainPIT()
+}
+
+// WorldEpoch runs World time steps through the end of the WorldChanges table
+func (ss *Sim) WorldEpoch() {
+	ss.GUI.StopNow = false
+	for {
+		ss.WorldStep()
+		mx := ss.WorldEnv.Tick.Max
+		if ss.GUI.StopNow || (mx > 0 && ss.WorldEnv.Tick.Cur == mx-1) {
+			break
+		}
+	}
+	ss.Stopped()
+}
+
 /*
 func (ss *Sim) ConfigPats() {
 	dt := ss.Pats
@@ -711,6 +1309,19 @@ func (ss *Sim) ConfigPats() {
 ////////////////////////////////////////////////////////////////////////////////////////////
 // 		Logging
 
+// ValsTsr gets value tensor of given name, creating if not yet made
+func (ss *Sim) ValsTsr(name string) *etensor.Float32 {
+	if ss.ValsTsrs == nil {
+		ss.ValsTsrs = make(map[string]*etensor.Float32)
+	}
+	tsr, ok := ss.ValsTsrs[name]
+	if !ok {
+		tsr = &etensor.Float32{}
+		ss.ValsTsrs[name] = tsr
+	}
+	return tsr
+}
+
 // RunName returns a name for this run that combines Tag and Params -- add this to
 // any file names that are saved.
 func (ss *Sim) RunName() string {
@@ -736,6 +1347,22 @@ func (ss *Sim) WeightsFileName() string {
 	return ss.Net.Nm + "_" + ss.RunName() + "_" + ss.RunEpochName(ss.TrainEnv.Run.Cur, ss.TrainEnv.Epoch.Cur) + ".wts"
 }
 
+// CheckpointFileName returns the weights file name for a checkpoint of the
+// given phase, which is the WeightsFileName with the phase added
+func (ss *Sim) CheckpointFileName(run, epc int, phase string) string {
+	return ss.Net.Nm + "_" + ss.RunName() + "_" + ss.RunEpochName(run, epc) + "_" + phase + ".wts"
+}
+
+// SaveCheckpoint saves the current weights as a checkpoint of the given phase,
+// and returns its file
+func (ss *Sim) SaveCheckpoint(run, epc int, phase string) string {
+	fnm, err := ss.Ckpts.Save(ss.Net, ss.CheckpointFileName(run, epc, phase), run, phase, epc, ss.Params.Name())
+	if err != nil {
+		log.Println(err)
+	}
+	return fnm
+}
+
 // LogFileName returns default log file name
 func (ss *Sim) LogFileName(lognm string) string {
 	return ss.Net.Nm + "_" + ss.RunName() + "_" + lognm + ".tsv"
@@ -751,6 +1378,31 @@ func (ss *Sim) InitStats() {
 	ss.Stats.SetFloat("TrlCosDiff", 0.0)
 	ss.Stats.SetInt("FirstZero", -1) // critical to reset to -1
 	ss.Stats.SetInt("NZero", 0)
+	ss.Stats.SetInt("TrlsToCrit", -1)
+	ss.Stats.SetInt("Day", 0)
+	ss.Stats.SetInt("Tick", 0)
+	ss.Stats.SetString("TickName", "")
+	ss.Stats.SetFloat("Hour", 0)
+	ss.Stats.SetFloat("Stress", 0)
+	ss.Stats.SetFloat("Reward", 0)
+	ss.Stats.SetFloat("RPE", 0)
+	ss.Stats.SetFloat("LrateMod", 1)
+	ss.Stats.SetFloat("DrugResp", 0)
+	for _, nm := range SymptomNames {
+		ss.Stats.SetFloat(nm, 0)
+	}
+	ss.Stats.SetFloat("TonicDA", float64(ss.TonicDA.Drive))
+	ss.Stats.SetInt("ChosenBeh", -1)
+	ss.Stats.SetString("ChosenBehName", "")
+	ss.Stats.SetInt("TargBeh", -1)
+	ss.Stats.SetInt("RespBeh", -1)
+	ss.Stats.SetFloat("ApproachAct", 0)
+	ss.Stats.SetFloat("AvoidAct", 0)
+	ss.Stats.SetInt("RT", 0)
+	var ms MotiveStats
+	ms.Compute(nil, nil) // none active
+	ms.SetStats(&ss.Stats, "")
+	ss.Stats.SetString("Phase", "")
 }
 
 // StatCounters saves current counters to Stats, so they are available for logging etc
@@ -783,6 +1435,146 @@ func (ss *Sim) TrialStats() {
 	} else {
 		ss.Stats.SetFloat("TrlErr", 0)
 	}
+	beh := ss.ValsTsr("Behavior")
+	out.UnitValsTensor(beh, "Targ")
+	ss.Stats.SetInt("TargBeh", ArgMaxBeh(beh.Values))
+	out.UnitValsTensor(beh, "ActM")
+	ss.Stats.SetInt("RespBeh", ArgMaxBeh(beh.Values))
+	ap := ss.Net.LayerByName("Approach").(leabra.LeabraLayer).AsLeabra()
+	av := ss.Net.LayerByName("Avoidance").(leabra.LeabraLayer).AsLeabra()
+	ss.Stats.SetFloat("ApproachAct", float64(ap.Pools[0].ActM.Avg))
+	ss.Stats.SetFloat("AvoidAct", float64(av.Pools[0].ActM.Avg))
+	ss.Stats.SetFloat("Reward", float64(ap.Pools[0].ActP.Avg))
+	apv := ss.ValsTsr("Approach")
+	avv := ss.ValsTsr("Avoidance")
+	ap.UnitValsTensor(apv, "ActM")
+	av.UnitValsTensor(avv, "ActM")
+	var ms MotiveStats
+	ms.Compute(apv.Values, avv.Values)
+	ms.SetStats(&ss.Stats, ss.MotiveName(ms.Winner))
+}
+
+// UpdateStress updates the chronic Stress level from the aversive experience on
+// this trial: Avoidance activity, threat EnviroFeatures and unmet InteroState needs.
+// Only called on training trials and World time steps -- testing does not change it.
+func (ss *Sim) UpdateStress() {
+	if ss.Stress.On {
+		av := ss.Net.LayerByName("Avoidance").(leabra.LeabraLayer).AsLeabra()
+		enviro := ss.ValsTsr("EnviroFeatures")
+		intero := ss.ValsTsr("InteroState")
+		ss.Net.LayerByName("EnviroFeatures").(leabra.LeabraLayer).AsLeabra().UnitValsTensor(enviro, "Act")
+		ss.Net.LayerByName("InteroState").(leabra.LeabraLayer).AsLeabra().UnitValsTensor(intero, "Act")
+		ss.Stress.Update(av.Pools[0].ActM.Avg, enviro.Values, intero.Values)
+	}
+	ss.Stats.SetFloat("Stress", float64(ss.Stress.Stress))
+}
+
+// UpdateTonicDA updates the tonic VTA drive from the Reward on this trial, which
+// is the plus-phase (outcome) activity of the Approach layer, and the DyDA activity.
+// Only called on training trials and World time steps -- testing does not change it.
+func (ss *Sim) UpdateTonicDA() {
+	ap := ss.Net.LayerByName("Approach").(leabra.LeabraLayer).AsLeabra()
+	dy := ss.Net.LayerByName("DyDA").(leabra.LeabraLayer).AsLeabra()
+	rew := ap.Pools[0].ActP.Avg
+	if ss.TonicDA.On {
+		ss.TonicDA.Update(rew, dy.Pools[0].ActP.Avg)
+		ss.ApplyTonicDA()
+	}
+	ss.Stats.SetFloat("TonicDA", float64(ss.TonicDA.Drive))
+}
+
+// UpdateSymptoms adds the symptom scores of this trial or time step to the given
+// sums, with the hour of the day in the World, or -1 for training and testing
+func (ss *Sim) UpdateSymptoms(sm *SymptomSums, hour float32) {
+	acts := make(map[string][]float32)
+	for _, lnm := range []string{"EnviroFeatures", "Approach", "Behavior", "Cost"} {
+		tsr := ss.ValsTsr(lnm)
+		ss.Net.LayerByName(lnm).(leabra.LeabraLayer).AsLeabra().UnitValsTensor(tsr, "ActM")
+		acts[lnm] = tsr.Values
+	}
+	ss.Symptoms.Step(sm, acts["EnviroFeatures"], acts["Approach"], acts["Behavior"], acts["Cost"], hour)
+}
+
+// EpochSymptoms sets the symptom stats from the sums over the epoch of the
+// given mode, and resets them for the next epoch
+func (ss *Sim) EpochSymptoms(mode etime.Modes) {
+	sm := &ss.Symptoms.Train
+	if mode == etime.Test {
+		sm = &ss.Symptoms.Test
+	}
+	ss.Symptoms.SetStats(&ss.Stats, sm)
+	sm.Reset()
+}
+
+// ComputeRPE computes the reward prediction error for the Behavior chosen on this
+// trial, from the expected (minus phase) and outcome (plus phase) activity of its
+// Approach motive -- or of the whole Approach layer if it has no Approach motive
+func (ss *Sim) ComputeRPE() {
+	ap := ss.Net.LayerByName("Approach").(leabra.LeabraLayer).AsLeabra()
+	exp, out := ap.Pools[0].ActM.Avg, ap.Pools[0].ActP.Avg
+	if k := BehMotive(ss.Stats.Int("ChosenBeh")); k >= 0 && k < len(ap.Neurons) {
+		exp, out = ap.Neurons[k].ActM, ap.Neurons[k].ActP
+	}
+	ss.RPE.Compute(exp, out)
+	ss.Stats.SetFloat("RPE", float64(ss.RPE.RPE))
+	ss.Stats.SetFloat("LrateMod", float64(ss.RPE.LrateMod()))
+}
+
+// ApplyRPE sets the learning rate of the Prjns gated by the RPE to the given
+// multiple of their unscaled learning rate
+func (ss *Sim) ApplyRPE(mod float32) {
+	for _, ly := range ss.Net.Layers {
+		for pi := 0; pi < ly.NRecvPrjns(); pi++ {
+			if pj := ly.RecvPrjn(pi); ss.RPE.Gates(pj.Name()) {
+				pj.(leabra.LeabraPrjn).AsLeabra().LrateMult(mod)
+			}
+		}
+	}
+}
+
+// ApplyTonicDA sets the baseline drive of the VTA layer to the tonic Drive,
+// which stays at the Base from the params if the adaptation is off
+func (ss *Sim) ApplyTonicDA() {
+	vta := ss.Net.LayerByName("VTA").(leabra.LeabraLayer).AsLeabra()
+	vta.Act.Noise.Mean = float64(ss.TonicDA.Drive)
+}
+
+// SelectBehavior selects a single Behavior from the ActM of the Behavior layer
+// according to the Select policy, and records it in the ChosenBeh stats
+func (ss *Sim) SelectBehavior() int {
+	out := ss.Net.LayerByName("Behavior").(leabra.LeabraLayer).AsLeabra()
+	acts := ss.ValsTsr("Behavior")
+	out.UnitValsTensor(acts, "ActM")
+	ss.Activation.BiasActs(acts.Values)
+	beh := ss.Activation.Force(ss.Select.Select(acts.Values))
+	ss.Stats.SetInt("ChosenBeh", beh)
+	ss.Stats.SetString("ChosenBehName", ss.BehName(beh))
+	return beh
+}
+
+// BehName returns the name of given Behavior, from the UnitLabels, or else the
+// Name column of the WorldEffects table -- empty if none
+func (ss *Sim) BehName(beh int) string {
+	if lab := ss.UnitLabels.Label("Behavior", beh); lab != "" {
+		return lab
+	}
+	if beh < 0 || beh >= ss.WorldEffects.Rows || ss.WorldEffects.ColByName("Name") == nil {
+		return ""
+	}
+	return ss.WorldEffects.CellString("Name", beh)
+}
+
+// MotiveName returns the name of given motive (Approach unit, then Avoidance),
+// from the UnitLabels, or else the name of its first Behavior -- empty if none
+func (ss *Sim) MotiveName(k int) string {
+	lnm, ui := "Approach", k
+	if napp := ss.Net.LayerByName("Approach").Shape().Len(); k >= napp {
+		lnm, ui = "Avoidance", k-napp
+	}
+	if lab := ss.UnitLabels.Label(lnm, ui); lab != "" {
+		return lab
+	}
+	return ss.BehName(MotiveBeh(k))
 }
 
 //////////////////////////////////////////////
@@ -795,6 +1587,7 @@ func (ss *Sim) ConfigLogs() {
 	// don't plot certain combinations we don't use
 	ss.Logs.NoPlot(etime.Train, etime.Cycle)
 	ss.Logs.NoPlot(etime.Test, etime.Run)
+	ss.Logs.NoPlot(etime.Train, etime.Tick)
 	// note: Analyze not plotted by default
 	ss.Logs.SetMeta(etime.Train, etime.Run, "LegendCol", "Params")
 }
@@ -803,13 +1596,19 @@ func (ss *Sim) ConfigLogs() {
 func (ss *Sim) Log(mode etime.Modes, time etime.Times) {
 	dt := ss.Logs.Table(mode, time)
 	row := dt.Rows
+	if time == etime.Epoch && (mode == etime.Train || mode == etime.Test) {
+		ss.EpochSymptoms(mode)
+	}
 	switch {
 	case mode == etime.Test && time == etime.Epoch:
 		ss.LogTestErrors()
+		ss.LogBehConfusion()
 	case time == etime.Cycle:
 		row = ss.Stats.Int("Cycle")
 	case time == etime.Trial:
 		row = ss.Stats.Int("Trial")
+	case time == etime.Tick:
+		row = ss.Stats.Int("Tick")
 	}
 
 	ss.Logs.LogRow(mode, time, row) // also logs to file, etc
@@ -841,6 +1640,34 @@ func (ss *Sim) LogTestErrors() {
 	ss.Logs.MiscTables["TestErrorStats"] = allsp.AggsToTable(etable.AddAggName)
 }
 
+// ConfigBehConfusion configures the confusion matrix and choice frequency
+// tables of the Behaviors, in MiscTables as BehConfusion and BehChoiceFreq
+func (ss *Sim) ConfigBehConfusion() {
+	names := make([]string, ss.Net.LayerByName("Behavior").Shape().Len())
+	for b := range names {
+		if names[b] = ss.BehName(b); names[b] == "" {
+			names[b] = fmt.Sprintf("Beh%d", b)
+		}
+	}
+	ss.BehConfusion.Config(names)
+	ss.Logs.MiscTables["BehConfusion"] = ss.BehConfusion.Matrix
+	ss.Logs.MiscTables["BehChoiceFreq"] = ss.BehConfusion.Freqs
+}
+
+// LogBehConfusion computes the confusion matrix of the target vs. produced
+// Behavior, and the choice frequencies, across TestTrials, at Test Epoch scope
+func (ss *Sim) LogBehConfusion() {
+	ss.BehConfusion.Compute(ss.Logs.Table(etime.Test, etime.Trial))
+	if ss.GUI.TabView == nil {
+		return
+	}
+	for _, nm := range []string{"BehConfusion", "BehChoiceFreq"} {
+		if tab, err := ss.GUI.TabView.TabByNameTry(nm); err == nil {
+			tab.(*etview.TableView).UpdateTable()
+		}
+	}
+}
+
 // LogRunStats records stats across all runs, at Train Run scope
 func (ss *Sim) LogRunStats() {
 	sk := etime.Scope(etime.Train, etime.Run)
@@ -877,6 +1704,12 @@ func (ss *Sim) ConfigGui() *gi.Window {
 	nv.Scene().Camera.Pose.Pos.Set(0, 1, 2.75) // more "head on" than default which is more "top down"
 	nv.Scene().Camera.LookAt(mat32.Vec3{0, 0, 0}, mat32.Vec3{0, 1, 0})
 	ss.GUI.AddPlots(title, &ss.Logs)
+	for _, nm := range []string{"BehConfusion", "BehChoiceFreq", "PITEffects"} {
+		tv := ss.GUI.TabView.AddNewTab(etview.KiT_TableView, nm).(*etview.TableView)
+		tv.SetTable(ss.Logs.MiscTables[nm], nil)
+	}
+	plt := ss.GUI.TabView.AddNewTab(eplot.KiT_Plot2D, "PITPlot").(*eplot.Plot2D)
+	plt.SetTable(ss.PITEffects)
 
 	ss.GUI.AddToolbarItem(egui.ToolbarItem{Label: "Init", Icon: "update",
 		Tooltip: "Initialize everything including network weights, and start over.  Also applies current params.",
@@ -1016,6 +1849,80 @@ func (ss *Sim) ConfigGui() *gi.Window {
 		},
 	})
 
+	ss.GUI.AddToolbarItem(egui.ToolbarItem{Label: "PIT Test",
+		Icon:    "fast-fwd",
+		Tooltip: "Runs the Pavlovian-Instrumental Transfer test: each Pavlovian cue in the instrumental context, with Approach and Avoidance left free, recording the shift of each Behavior over the baseline with no cue in PITEffects, plotted in PITPlot.",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			if !ss.GUI.IsRunning {
+				ss.GUI.IsRunning = true
+				ss.GUI.ToolBar.UpdateActions()
+				go ss.RunPITTest()
+			}
+		},
+	})
+
+	////////////////////////////////////////////////
+	ss.GUI.ToolBar.AddSeparator("world")
+	ss.GUI.AddToolbarItem(egui.ToolbarItem{Label: "World Step",
+		Icon:    "step-fwd",
+		Tooltip: "Runs one time step of the closed-loop World -- the chosen Behavior changes the World for the next step.",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			if !ss.GUI.IsRunning {
+				ss.GUI.IsRunning = true
+				ss.WorldStep()
+				ss.GUI.IsRunning = false
+				ss.GUI.UpdateWindow()
+			}
+		},
+	})
+	ss.GUI.AddToolbarItem(egui.ToolbarItem{Label: "Gen World",
+		Icon:    "new",
+		Tooltip: "Generates new stochastic WorldChanges from the WorldEvents rates, using the random seed for the current run, and saves them to a file.",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			ss.GenWorldChanges()
+			ss.GUI.UpdateWindow()
+		},
+	})
+	ss.GUI.AddToolbarItem(egui.ToolbarItem{Label: "World Epoch",
+		Icon:    "fast-fwd",
+		Tooltip: "Runs closed-loop World time steps through the end of the WorldChanges table.",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			if !ss.GUI.IsRunning {
+				ss.GUI.IsRunning = true
+				ss.GUI.ToolBar.UpdateActions()
+				go ss.WorldEpoch()
+			}
+		},
+	})
+	ss.GUI.AddToolbarItem(egui.ToolbarItem{Label: "Helplessness",
+		Icon:    "fast-fwd",
+		Tooltip: "Runs the learned helplessness protocol in the World: inescapable aversive events, then a test of escape, logging the escape latency of each episode in HelplessLog.",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			if !ss.GUI.IsRunning {
+				ss.GUI.IsRunning = true
+				ss.GUI.ToolBar.UpdateActions()
+				go ss.RunHelplessness()
+			}
+		},
+	})
+	ss.GUI.AddToolbarItem(egui.ToolbarItem{Label: "Run Cohort",
+		Icon:    "fast-fwd",
+		Tooltip: "Samples a synthetic Cohort of individuals with different motive biases, VTA baseline and DyDA sensitivity, and trains, tests and runs each one in the World, with one row per individual in CohortSummary.",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			if !ss.GUI.IsRunning {
+				ss.GUI.IsRunning = true
+				ss.GUI.ToolBar.UpdateActions()
+				go ss.RunCohortGUI()
+			}
+		},
+	})
+
 	////////////////////////////////////////////////
 	ss.GUI.ToolBar.AddSeparator("log")
 	ss.GUI.AddToolbarItem(egui.ToolbarItem{Label: "Reset RunLog",
@@ -1052,6 +1959,15 @@ func (ss *Sim) ConfigGui() *gi.Window {
 // These props register Save methods so they can be used
 var SimProps = ki.Props{
 	"CallMethods": ki.PropSlice{
+		{"OpenDrugs", ki.Props{
+			"desc": "open a schedule of pharmacological manipulations from a drug table",
+			"icon": "file-open",
+			"Args": ki.PropSlice{
+				{"File Name", ki.Props{
+					"ext": ".tsv",
+				}},
+			},
+		}},
 		{"SaveWeights", ki.Props{
 			"desc": "save network weights to file",
 			"icon": "file-save",
@@ -1071,6 +1987,14 @@ func (ss *Sim) CmdArgs() {
 	var saveRunLog bool
 	var saveNetData bool
 	var note string
+	var simDays int
+	var loadWts string
+	var helpless bool
+	var activ string
+	var drugs string
+	var cohort int
+	var curric string
+	var pit bool
 	flag.StringVar(&ss.Params.ExtraSets, "params", "", "ParamSet name to use -- must be valid name as listed in compiled-in params or loaded params")
 	flag.StringVar(&ss.Tag, "tag", "", "extra tag to add to file names saved from this run")
 	flag.StringVar(&note, "note", "", "user note -- describe the run params etc")
@@ -1081,9 +2005,26 @@ func (ss *Sim) CmdArgs() {
 	flag.BoolVar(&saveEpcLog, "epclog", true, "if true, save train epoch log to file")
 	flag.BoolVar(&saveRunLog, "runlog", true, "if true, save run epoch log to file")
 	flag.BoolVar(&saveNetData, "netdata", false, "if true, save network activation etc data from testing trials, for later viewing in netview")
+	flag.IntVar(&simDays, "simulate-days", 0, "if > 0, run the closed-loop World for this many simulated days with the trained weights from -load-wts instead of training, saving a timeline log with one row per time step")
+	flag.StringVar(&loadWts, "load-wts", "", "trained weights file to load for -simulate-days, -pit or -helpless")
+	flag.BoolVar(&pit, "pit", false, "if true, run the Pavlovian-Instrumental Transfer test instead of training, saving the PIT effects of each cue to a file")
+	flag.StringVar(&ss.Ckpts.Dir, "ckpt-dir", "", "directory for the weights checkpoints and their manifest, checkpoints.tsv")
+	flag.StringVar(&ss.Ckpts.Start, "start-wts", "", "checkpoint file that the first phase of the -curriculum starts from -- default is the INIT checkpoint of the run")
+	flag.StringVar(&curric, "curriculum", "", "if set, train on the phases of this curriculum table (e.g., PvlvThenInstr.tsv) with TrainPIT instead of training")
+	flag.StringVar(&activ, "activation", "", "if set with -simulate-days, apply the behavioral activation intervention in this mode (ActForce, ActBias or ActLowCost), saving the mean VTA, DyDA and Approach activity per day to a file")
+	flag.StringVar(&drugs, "drugs", "", "drug table (e.g., Drugs.tsv) with a schedule of pharmacological manipulations to apply in training and in the World")
+	flag.IntVar(&cohort, "cohort", 0, "if > 0, sample a synthetic cohort of this many individuals and train, test and run each one in the World instead of training, saving the traits and outcomes of each individual to a file -- trained on the -curriculum, if given")
+	flag.Int64Var(&ss.Cohort.Seed, "cohort-seed", 1, "random seed for sampling the traits of the -cohort")
+	flag.BoolVar(&helpless, "helpless", false, "if true, run the learned helplessness protocol in the World instead of training, saving the escape latency of each episode to a file")
 	flag.BoolVar(&nogui, "nogui", true, "if not passing any other args and want to run nogui, use nogui")
 	flag.Parse()
 	ss.Init()
+	if drugs != "" {
+		if err := ss.OpenDrugs(gi.FileName(drugs)); err != nil {
+			log.Println(err)
+			return
+		}
+	}
 
 	if note != "" {
 		fmt.Printf("note: %s\n", note)
@@ -1091,7 +2032,80 @@ func (ss *Sim) CmdArgs() {
 	if ss.Params.ExtraSets != "" {
 		fmt.Printf("Using ParamSet: %s\n", ss.Params.ExtraSets)
 	}
-
+	if simDays > 0 {
+		if activ != "" {
+			if err := ss.Activation.Mode.FromString(activ); err != nil {
+				log.Println(err)
+				return
+			}
+			ss.Activation.On = true
+		}
+		if loadWts == "" {
+			log.Println("-simulate-days requires the trained weights file to load, with -load-wts")
+			os.Exit(1)
+		}
+		if err := ss.SimulateDays(simDays, loadWts); err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		ss.Logs.CloseLogFiles()
+		if ss.Activation.On {
+			fnm := ss.LogFileName("activation")
+			fmt.Printf("Saving behavioral activation days to: %s\n", fnm)
+			if err := ss.ActivationLog.SaveCSV(gi.FileName(fnm), etable.Tab, etable.Headers); err != nil {
+				log.Println(err)
+			}
+		}
+		return
+	}
+	if cohort > 0 {
+		if curric != "" {
+			if err := ss.Trn.OpenCSV(gi.FileName(curric), etable.Tab); err != nil {
+				log.Println(err)
+				return
+			}
+		}
+		ss.Cohort.N = cohort
+		ss.TrainEnv.Run.Set(ss.StartRun)
+		ss.RunCohort()
+		fnm := ss.LogFileName("cohort")
+		fmt.Printf("Saving cohort summary to: %s\n", fnm)
+		if err := ss.CohortSummary.SaveCSV(gi.FileName(fnm), etable.Tab, etable.Headers); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+	if helpless {
+		if loadWts != "" {
+			if err := ss.Net.OpenWtsJSON(gi.FileName(loadWts)); err != nil {
+				log.Println(err)
+				return
+			}
+		}
+		ss.WorldEnv.Init(ss.TrainEnv.Run.Cur)
+		ss.Helplessness()
+		fnm := ss.LogFileName("helpless")
+		fmt.Printf("Escape latency: %g, learning rate: %g, saving episodes to: %s\n", ss.Helpless.Latency, ss.Helpless.Rate, fnm)
+		if err := ss.HelplessLog.SaveCSV(gi.FileName(fnm), etable.Tab, etable.Headers); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+	if pit {
+		if loadWts != "" {
+			if err := ss.Net.OpenWtsJSON(gi.FileName(loadWts)); err != nil {
+				log.Println(err)
+				return
+			}
+		}
+		ss.PITTest()
+		fnm := ss.LogFileName("pit")
+		fmt.Printf("Saving PIT effects to: %s\n", fnm)
+		if err := ss.PITEffects.SaveCSV(gi.FileName(fnm), etable.Tab, etable.Headers); err != nil {
+			log.Println(err)
+		}
+		return
+	}
 	if saveEpcLog {
 		fnm := ss.LogFileName("epc")
 		ss.Logs.SetLogFile(etime.Train, etime.Epoch, fnm)
@@ -1100,6 +2114,22 @@ func (ss *Sim) CmdArgs() {
 		fnm := ss.LogFileName("run")
 		ss.Logs.SetLogFile(etime.Train, etime.Run, fnm)
 	}
+	if curric != "" {
+		if err := ss.Trn.OpenCSV(gi.FileName(curric), etable.Tab); err != nil {
+			log.Println(err)
+			return
+		}
+		if saveEpcLog { // for the Test phases
+			ss.Logs.SetLogFile(etime.Test, etime.Epoch, ss.LogFileName("tst_epc"))
+		}
+		for run := ss.StartRun; run < ss.StartRun+ss.MaxRuns; run++ {
+			ss.TrainEnv.Run.Set(run)
+			ss.NewRun()
+			ss.TrainPIT()
+		}
+		ss.Logs.CloseLogFiles()
+		return
+	}
 	if saveNetData {
 		ss.NetData = &netview.NetData{}
 		ss.NetData.Init(ss.Net, 200, true) // 200 = amount to save
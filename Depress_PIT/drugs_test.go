@@ -0,0 +1,167 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+	"github.com/emer/leabra/leabra"
+)
+
+// testDrugs returns a drug table with the given Scale and Hill of each drug,
+// which all ramp in over 10, stay for 10, and ramp out over 10 from time 5
+func testDrugs(scales []string, hills []float64) *etable.Table {
+	sch := etable.Schema{
+		{"Name", etensor.STRING, nil, nil},
+		{"Sel", etensor.STRING, nil, nil},
+		{"Param", etensor.STRING, nil, nil},
+		{"Scale", etensor.STRING, nil, nil},
+		{"Start", etensor.FLOAT64, nil, nil},
+		{"Onset", etensor.FLOAT64, nil, nil},
+		{"Dur", etensor.FLOAT64, nil, nil},
+		{"Offset", etensor.FLOAT64, nil, nil},
+		{"Dose", etensor.FLOAT64, nil, nil},
+		{"Hill", etensor.FLOAT64, nil, nil},
+		{"Emax", etensor.FLOAT64, nil, nil},
+	}
+	dt := etable.New(sch, len(scales))
+	for i := range scales {
+		dt.SetCellString("Name", i, "Drug")
+		dt.SetCellString("Sel", i, "#VTA")
+		dt.SetCellString("Param", i, "Layer.Act.Gbar.L")
+		dt.SetCellString("Scale", i, scales[i])
+		dt.SetCellFloat("Start", i, 5)
+		dt.SetCellFloat("Onset", i, 10)
+		dt.SetCellFloat("Dur", i, 10)
+		dt.SetCellFloat("Offset", i, 10)
+		dt.SetCellFloat("Dose", i, 1)
+		dt.SetCellFloat("Hill", i, hills[i])
+		dt.SetCellFloat("Emax", i, -0.5)
+	}
+	return dt
+}
+
+func TestDrugLevel(t *testing.T) {
+	dr := &Drug{Start: 5, Onset: 10, Dur: 10, Offset: 10, Dose: 2, Hill: 1}
+	tests := []struct {
+		t    float64
+		want float64
+	}{
+		{0, 0},
+		{5, 0},
+		{10, 1},
+		{15, 2},
+		{20, 2},
+		{25, 2},
+		{30, 1},
+		{35, 0},
+		{100, 0},
+	}
+	for _, tt := range tests {
+		if got := dr.Level(tt.t); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Level(%v): got %v, want %v", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestDrugResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		dose float64
+		hill float64
+		want float64
+	}{
+		{"no drug", 0, 1, 0},
+		{"no drug, steep", 0, 4, 0},
+		{"EC50", 1, 1, 0.5},
+		{"EC50, steep", 1, 3, 0.5},
+		{"twice EC50", 2, 1, 2.0 / 3.0},
+		{"twice EC50, Hill 2", 2, 2, 0.8},
+		{"half EC50, Hill 2", 0.5, 2, 0.2},
+	}
+	for _, tt := range tests {
+		dr := &Drug{Dur: 10, Dose: tt.dose, Hill: tt.hill}
+		if got := dr.Response(5); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%v: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDrugSchedSetTable(t *testing.T) {
+	tests := []struct {
+		name  string
+		scale string
+		hill  float64
+		valid bool
+	}{
+		{"ticks", "Tick", 1, true},
+		{"epochs", "Epoch", 2, true},
+		{"invalid Scale", "Day", 1, false},
+		{"zero Hill", "Tick", 0, false},
+		{"negative Hill", "Tick", -1, false},
+	}
+	for _, tt := range tests {
+		ds := &DrugSched{}
+		if err := ds.SetTable(testDrugs([]string{tt.scale}, []float64{tt.hill})); (err == nil) != tt.valid {
+			t.Errorf("%v: got error %v, want valid: %v", tt.name, err, tt.valid)
+		}
+	}
+	ds := &DrugSched{}
+	if err := ds.SetTable(etable.New(etable.Schema{{"Name", etensor.STRING, nil, nil}}, 1)); err == nil {
+		t.Errorf("expected an error for a table with no Sel column")
+	}
+}
+
+func TestDrugSchedApply(t *testing.T) {
+	net := &leabra.Network{}
+	net.InitName(net, "Test")
+	net.AddLayer2D("VTA", 1, 1, emer.Input)
+	net.Defaults()
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	vta := net.LayerByName("VTA").(leabra.LeabraLayer).AsLeabra()
+	base := vta.Act.Gbar.L
+	ds := &DrugSched{}
+	if err := ds.SetTable(testDrugs([]string{"Tick", "Tick", "Epoch"}, []float64{1, 1, 1})); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.Validate(net); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		t    float64
+		resp float64 // Response of each Tick drug
+	}{
+		{0, 0},
+		{10, 1.0 / 3.0},
+		{20, 0.5},
+		{40, 0},
+	}
+	for _, tt := range tests {
+		if got := ds.Apply(net, "Tick", tt.t); math.Abs(got-tt.resp) > 1e-6 {
+			t.Errorf("t %v: Response: got %v, want %v", tt.t, got, tt.resp)
+		}
+		want := base * float32(1-0.5*2*tt.resp) // the two Tick drugs add up
+		if math.Abs(float64(vta.Act.Gbar.L-want)) > 1e-6 {
+			t.Errorf("t %v: Gbar.L: got %v, want %v", tt.t, vta.Act.Gbar.L, want)
+		}
+	}
+	ds.Apply(net, "Tick", 20)
+	ds.Restore(net)
+	if vta.Act.Gbar.L != base {
+		t.Errorf("Restore: Gbar.L: got %v, want the baseline %v", vta.Act.Gbar.L, base)
+	}
+	bad := &DrugSched{}
+	bad.SetTable(testDrugs([]string{"Tick"}, []float64{1}))
+	bad.Drugs[0].Sel = "#Hidden"
+	if err := bad.Validate(net); err == nil {
+		t.Errorf("expected an error for a drug on an unknown layer")
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// CurricMix interleaves the trials of the phases of a Mix within each epoch:
+// its Table has the patterns of all the phases, in the ratio of their Ratio,
+// with a Src column with the phase of each row, and each trial is run with
+// the layer types and lesions of its phase.
+type CurricMix struct {
+	Name   string         `desc:"name of the Mix"`
+	Phases []*CurricPhase `desc:"phases whose trials are interleaved"`
+	Table  *etable.Table  `view:"no-inline" desc:"patterns of one epoch, from all the phases, with the index of the phase of each row in the Src column"`
+	Cur    int            `inactive:"+" desc:"index of the phase whose layer types and lesions are applied -- -1 = none"`
+	Rand   *rand.Rand     `view:"-" desc:"random source of the subsample of the patterns of each phase that fills its share beyond the whole repeats -- redrawn every epoch"`
+	types  map[string]emer.LayerType
+	pats   []*etable.Table // patterns of each phase
+	subs   []int           // first row of the subsample of each phase in the Table
+	nsubs  []int           // number of rows of the subsample of each phase
+}
+
+// NewCurricMix returns the Mix of the given phases, with the pattern table of
+// each phase, which must all have the columns of the first one.  An epoch has
+// as many trials as all the patterns together, shared among the phases by
+// their Ratio, with each pattern repeated as needed to fill the share of its
+// phase, and the rest of the share filled by a subsample of its patterns,
+// drawn with the given random seed, e.g., that of the run.
+func NewCurricMix(phases []*CurricPhase, pats []*etable.Table, seed int64) (*CurricMix, error) {
+	cm := &CurricMix{Name: phases[0].Mix, Phases: phases, Cur: -1}
+	cm.Rand = rand.New(rand.NewSource(seed))
+	cm.pats = pats
+	cm.subs = make([]int, len(phases))
+	cm.nsubs = make([]int, len(phases))
+	ntot := 0
+	var rtot float64
+	ratios := make([]float64, len(phases))
+	for i, cp := range phases {
+		for ci := range pats[0].Cols {
+			if pats[i].ColByName(pats[0].ColNames[ci]) == nil {
+				return nil, fmt.Errorf("CurricMix %v: patterns %v have no %v column", cm.Name, cp.Patterns, pats[0].ColNames[ci])
+			}
+		}
+		ntot += pats[i].Rows
+		ratios[i] = cp.Ratio
+		if ratios[i] <= 0 {
+			ratios[i] = float64(pats[i].Rows)
+		}
+		rtot += ratios[i]
+	}
+	sch := append(pats[0].Schema(), etable.Column{"Src", etensor.INT64, nil, nil})
+	dt := etable.New(sch, 0)
+	cm.Table = dt
+	dt.SetMetaData("name", cm.Name)
+	dt.SetMetaData("desc", "interleaved patterns of the phases of the Mix")
+	for i := range phases {
+		pt := pats[i]
+		if pt.Rows == 0 {
+			continue
+		}
+		n := int(math.Round(float64(ntot) * ratios[i] / rtot))
+		st := dt.Rows
+		dt.AddRows(n)
+		j := 0
+		for ; j+pt.Rows <= n; j += pt.Rows { // whole repeats of the patterns
+			for r := 0; r < pt.Rows; r++ {
+				cm.CopyRow(st+j+r, i, r)
+			}
+		}
+		cm.subs[i] = st + j
+		cm.nsubs[i] = n - j
+		for r := st; r < st+n; r++ {
+			dt.SetCellFloat("Src", r, float64(i))
+		}
+	}
+	cm.Sample()
+	return cm, nil
+}
+
+// Sample draws a new subsample of the patterns of each phase to fill the
+// rest of its share of the Table beyond the whole repeats, e.g., at the
+// start of each epoch
+func (cm *CurricMix) Sample() {
+	for i, pt := range cm.pats {
+		if cm.nsubs[i] == 0 {
+			continue
+		}
+		for j, r := range cm.Rand.Perm(pt.Rows)[:cm.nsubs[i]] {
+			cm.CopyRow(cm.subs[i]+j, i, r)
+		}
+	}
+}
+
+// CopyRow copies the given row of the patterns of the given phase to the
+// given row of the Table
+func (cm *CurricMix) CopyRow(row, phase, prow int) {
+	for _, cnm := range cm.pats[0].ColNames {
+		cm.Table.CopyCell(cnm, row, cm.pats[phase], cnm, prow)
+	}
+}
+
+// SetTrial applies the layer types and lesions of the phase of the given row
+// of the Table, if it is not already applied
+func (cm *CurricMix) SetTrial(net emer.Network, row int) {
+	src := int(cm.Table.CellFloat("Src", row))
+	if src == cm.Cur {
+		return
+	}
+	cm.Restore(net)
+	cm.types = cm.Phases[src].Apply(net)
+	cm.Cur = src
+}
+
+// Restore restores the layer types and lesions before the current phase
+func (cm *CurricMix) Restore(net emer.Network) {
+	if cm.Cur < 0 {
+		return
+	}
+	cm.Phases[cm.Cur].Restore(net, cm.types)
+	cm.Cur = -1
+}
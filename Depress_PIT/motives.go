@@ -0,0 +1,98 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/emer/emergent/estats"
+	"github.com/emer/etable/etable"
+)
+
+// MotiveStats are the trial-level stats of the Approach and Avoidance motive
+// layers, from their minus phase (expectation) activity.  The motives are
+// indexed as in BehMotive: the Approach units, then the Avoidance units.
+type MotiveStats struct {
+	ApproachTot float32 `desc:"total activity of the Approach layer"`
+	AvoidTot    float32 `desc:"total activity of the Avoidance layer"`
+	ApproachMax float32 `desc:"activity of the most active Approach unit"`
+	AvoidMax    float32 `desc:"activity of the most active Avoidance unit"`
+	Balance     float32 `desc:"approach - avoid balance: ApproachMax - AvoidMax, from -1 (avoid) to 1 (approach)"`
+	Conflict    float32 `desc:"approach - avoid conflict: co-activation of both motives, as the smaller of ApproachMax and AvoidMax"`
+	Winner      int     `desc:"index of the most active motive -- -1 if none is active"`
+}
+
+// Compute computes the stats from the activities of the Approach and Avoidance units
+func (ms *MotiveStats) Compute(appr, av []float32) {
+	*ms = MotiveStats{Winner: -1}
+	var win float32
+	for i, a := range appr {
+		ms.ApproachTot += a
+		if a > ms.ApproachMax {
+			ms.ApproachMax = a
+		}
+		if a > win {
+			win = a
+			ms.Winner = i
+		}
+	}
+	for i, a := range av {
+		ms.AvoidTot += a
+		if a > ms.AvoidMax {
+			ms.AvoidMax = a
+		}
+		if a > win {
+			win = a
+			ms.Winner = len(appr) + i
+		}
+	}
+	ms.Balance = ms.ApproachMax - ms.AvoidMax
+	ms.Conflict = ms.ApproachMax
+	if ms.AvoidMax < ms.Conflict {
+		ms.Conflict = ms.AvoidMax
+	}
+}
+
+// SetStats sets the stats, with the name of the winning motive
+func (ms *MotiveStats) SetStats(stats *estats.Stats, winName string) {
+	stats.SetFloat("ApproachTot", float64(ms.ApproachTot))
+	stats.SetFloat("AvoidTot", float64(ms.AvoidTot))
+	stats.SetFloat("ApproachMax", float64(ms.ApproachMax))
+	stats.SetFloat("AvoidMax", float64(ms.AvoidMax))
+	stats.SetFloat("AppAvBalance", float64(ms.Balance))
+	stats.SetFloat("AppAvConflict", float64(ms.Conflict))
+	stats.SetInt("WinMotive", ms.Winner)
+	stats.SetString("WinMotiveName", winName)
+}
+
+// MotiveBeh returns the first of the two Behaviors of given motive, which
+// names the motive -- the inverse of BehMotive -- -1 if none
+func MotiveBeh(k int) int {
+	if k < 0 {
+		return -1
+	}
+	return 2 * k
+}
+
+// ModalString returns the most frequent non-empty value of the given string
+// column of the table, e.g., the motive that wins most often over the trials
+// of an epoch -- the one reaching that count first in case of a tie, empty if none
+func ModalString(dt *etable.Table, col string) string {
+	cl := dt.ColByName(col)
+	if cl == nil {
+		return ""
+	}
+	cnts := make(map[string]int)
+	mode, max := "", 0
+	for row := 0; row < dt.Rows; row++ {
+		v := cl.StringVal1D(row)
+		if v == "" {
+			continue
+		}
+		cnts[v]++
+		if cnts[v] > max {
+			mode, max = v, cnts[v]
+		}
+	}
+	return mode
+}
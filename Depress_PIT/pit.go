@@ -0,0 +1,126 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/emer/etable/etable"
+	"github.com/emer/etable/etensor"
+)
+
+// PITParams are the parameters of the Pavlovian-Instrumental Transfer test,
+// which presents each Pavlovian cue on the Cues layers, in the instrumental
+// context of the Inputs layers, with all other layers left free, and measures
+// how much it shifts the Behavior activity over the instrumental baseline,
+// which has the same context but no cue.  Specific transfer is the shift of
+// the Behaviors of the motive that the cue predicts, and general transfer is
+// the shift of all the other Behaviors.
+type PITParams struct {
+	Patterns string    `desc:"file name of the pattern table with the Pavlovian cues, one per row"`
+	Cues     []string  `desc:"names of the layers with the cue in the patterns -- the only inputs that differ between the baseline and the cue trials"`
+	Instr    string    `desc:"file name of the instrumental pattern table, from which the Inputs are taken"`
+	Inputs   []string  `desc:"names of the layers of the instrumental inputs other than the cues, applied in both the baseline and the cue trials, as their mean over the Instr patterns -- all other layers are left free"`
+	Motives  []string  `desc:"names of the layers with the outcome predicted by the cue in the patterns, in motive order (e.g., Approach then Avoidance)"`
+	Base     []float32 `inactive:"+" desc:"Behavior activity (ActM) for the instrumental baseline with no cue"`
+}
+
+func (pp *PITParams) Defaults() {
+	pp.Patterns = "DepressPvlv.tsv"
+	pp.Cues = []string{"EnviroFeatures", "InteroState"}
+	pp.Instr = "DepressInstr.tsv"
+	pp.Inputs = []string{"MBApp", "MBAv", "Cost", "DyDA"}
+	pp.Motives = []string{"Approach", "Avoidance"}
+}
+
+// Context returns the instrumental context of the test: the mean pattern of
+// each of the Inputs layers over the rows of the instrumental patterns
+func (pp *PITParams) Context(instr *etable.Table) (map[string]*etensor.Float32, error) {
+	ctx := make(map[string]*etensor.Float32)
+	for _, lnm := range pp.Inputs {
+		cl := instr.ColByName(lnm)
+		if cl == nil {
+			return nil, fmt.Errorf("PITParams: instrumental patterns %v have no %v layer", pp.Instr, lnm)
+		}
+		tsr := etensor.NewFloat32(cl.Shapes()[1:], nil, nil)
+		for row := 0; row < instr.Rows; row++ {
+			pat := instr.CellTensor(lnm, row)
+			for i := range tsr.Values {
+				tsr.Values[i] += float32(pat.FloatVal1D(i))
+			}
+		}
+		if instr.Rows > 0 {
+			for i := range tsr.Values {
+				tsr.Values[i] /= float32(instr.Rows)
+			}
+		}
+		ctx[lnm] = tsr
+	}
+	return ctx, nil
+}
+
+// CueMotive returns the motive predicted by the cue in the given row of the
+// patterns: the most active unit over all the Motives layers, or -1 if none is active
+func (pp *PITParams) CueMotive(pats *etable.Table, row int) int {
+	mot, k := -1, 0
+	var max float64
+	for _, lnm := range pp.Motives {
+		tsr := pats.CellTensor(lnm, row)
+		if tsr == nil {
+			continue
+		}
+		for i := 0; i < tsr.Len(); i++ {
+			if v := tsr.FloatVal1D(i); v > max {
+				mot, max = k+i, v
+			}
+		}
+		k += tsr.Len()
+	}
+	return mot
+}
+
+// Transfer returns the specific transfer, the mean effect over the Behaviors
+// of the given motive, and the general transfer, the mean effect over all the
+// other Behaviors.  Specific is 0 if motive is -1.
+func (pp *PITParams) Transfer(effects []float32, motive int) (spec, gen float32) {
+	var nspec, ngen int
+	for b, ef := range effects {
+		if BehMotive(b) == motive {
+			spec += ef
+			nspec++
+		} else {
+			gen += ef
+			ngen++
+		}
+	}
+	if nspec > 0 {
+		spec /= float32(nspec)
+	}
+	if ngen > 0 {
+		gen /= float32(ngen)
+	}
+	return
+}
+
+// ConfigEffects configures the table of PIT effects, with one row per cue
+// (the first row is the baseline) and the effect on each of nbeh Behaviors
+func (pp *PITParams) ConfigEffects(dt *etable.Table, nbeh int) {
+	sch := etable.Schema{
+		{"Cue", etensor.INT64, nil, nil},
+		{"CueName", etensor.STRING, nil, nil},
+		{"Motive", etensor.INT64, nil, nil},
+		{"Specific", etensor.FLOAT64, nil, nil},
+		{"General", etensor.FLOAT64, nil, nil},
+		{"Act", etensor.FLOAT32, []int{1, nbeh}, []string{"Y", "X"}},
+		{"Effect", etensor.FLOAT32, []int{1, nbeh}, []string{"Y", "X"}},
+	}
+	dt.SetFromSchema(sch, 0)
+	dt.SetMetaData("name", "PITEffects")
+	dt.SetMetaData("desc", "Pavlovian-Instrumental Transfer: shift of each Behavior by each cue over the baseline, with specific and general transfer")
+	dt.SetMetaData("Type", "Bar")
+	dt.SetMetaData("XAxisCol", "Cue")
+	dt.SetMetaData("Specific:On", "+")
+	dt.SetMetaData("General:On", "+")
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import "math"
+
+// RPEParams compute a phasic dopamine reward prediction error (RPE) on each
+// trial, as the outcome (plus phase) minus the expected (minus phase) Approach
+// value for the chosen Behavior, and gate learning in the Approach pathway by it:
+// the learning rate of the Prjns is multiplied by LrateMod.
+type RPEParams struct {
+	On       bool     `desc:"if true, learning in the Prjns is gated by the RPE -- on in the RPEGate ParamSet"`
+	Base     float32  `viewif:"On" def:"0.2" desc:"learning rate multiplier when there is no RPE"`
+	Gain     float32  `viewif:"On" def:"2" desc:"increase in the learning rate multiplier per unit of absolute RPE"`
+	Max      float32  `viewif:"On" def:"1" desc:"maximum learning rate multiplier"`
+	Prjns    []string `viewif:"On" desc:"names of the Prjns in the Approach pathway whose learning is gated"`
+	Expected float32  `inactive:"+" desc:"expected Approach value for the chosen Behavior (minus phase)"`
+	Outcome  float32  `inactive:"+" desc:"Approach outcome for the chosen Behavior (plus phase)"`
+	RPE      float32  `inactive:"+" desc:"reward prediction error: Outcome - Expected"`
+}
+
+func (rp *RPEParams) Defaults() {
+	rp.On = false
+	rp.Base = 0.2
+	rp.Gain = 2
+	rp.Max = 1
+	rp.Prjns = []string{"Hidden1ToApproach", "ApproachToHidden1", "ApproachToHidden2", "Hidden2ToApproach"}
+}
+
+// Compute computes the RPE from the expected and outcome values
+func (rp *RPEParams) Compute(exp, out float32) {
+	rp.Expected = exp
+	rp.Outcome = out
+	rp.RPE = out - exp
+}
+
+// LrateMod returns the learning rate multiplier for the current RPE -- 1 if not On
+func (rp *RPEParams) LrateMod() float32 {
+	if !rp.On {
+		return 1
+	}
+	mod := rp.Base + rp.Gain*float32(math.Abs(float64(rp.RPE)))
+	if mod > rp.Max {
+		mod = rp.Max
+	}
+	return mod
+}
+
+// Gates returns true if learning in the Prjn of given name is gated by the RPE
+func (rp *RPEParams) Gates(prjn string) bool {
+	for _, pnm := range rp.Prjns {
+		if pnm == prjn {
+			return true
+		}
+	}
+	return false
+}
+
+// BehMotive returns the index of the motive (Approach unit, then Avoidance)
+// for given Behavior -- each motive has two Behaviors -- -1 if none
+func BehMotive(beh int) int {
+	if beh < 0 {
+		return -1
+	}
+	return beh / 2
+}
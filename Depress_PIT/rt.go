@@ -0,0 +1,83 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+// RTParams are the parameters of the settling time (reaction time) of the
+// Behavior layer on testing trials and World time steps: the number of minus
+// phase cycles until the most active Behavior unit first reaches Thr, or the
+// same unit has been the most active, above MinAct, for Stable cycles in a row,
+// whichever comes first.  Slower decisions, e.g., under high DyDA or low VTA, are an
+// analogue of psychomotor retardation.
+type RTParams struct {
+	On     bool    `desc:"if true, the settling time is measured when testing"`
+	Thr    float32 `viewif:"On" def:"0.5" desc:"activity of the most active Behavior unit at which it is settled"`
+	Stable int     `viewif:"On" def:"10" desc:"number of cycles in a row with the same most active Behavior unit at which it is settled -- 0 = not used"`
+	MinAct float32 `viewif:"On" def:"0.1" desc:"minimum activity of the most active Behavior unit for it to count as a stable winner"`
+	Stop   bool    `viewif:"On" desc:"if true, the remaining minus phase cycles are skipped once settled, so the minus phase activity is that at the settling time -- the plus phase is still run, for the stats"`
+	Cycle  int     `inactive:"+" desc:"cycle at which the Behavior layer settled on this trial -- -1 = not yet"`
+	win    int     // most active unit on the last cycle
+	nwin   int     // number of cycles in a row that win has been the most active
+}
+
+func (rp *RTParams) Defaults() {
+	rp.On = true
+	rp.Thr = 0.5
+	rp.Stable = 10
+	rp.MinAct = 0.1
+	rp.Init()
+}
+
+// Init initializes the state at the start of a trial
+func (rp *RTParams) Init() {
+	rp.Cycle = -1
+	rp.win = -1
+	rp.nwin = 0
+}
+
+// Update updates the state from the Behavior activities on given cycle,
+// returning true if it has settled on this cycle or before
+func (rp *RTParams) Update(acts []float32, cyc int) bool {
+	if rp.Cycle >= 0 {
+		return true
+	}
+	maxi := -1
+	var max float32
+	for i, a := range acts {
+		if maxi < 0 || a > max {
+			maxi = i
+			max = a
+		}
+	}
+	if maxi < 0 {
+		return false
+	}
+	if max >= rp.Thr {
+		rp.Cycle = cyc
+		return true
+	}
+	if max < rp.MinAct {
+		rp.win, rp.nwin = -1, 0
+		return false
+	}
+	if maxi == rp.win {
+		rp.nwin++
+	} else {
+		rp.win, rp.nwin = maxi, 1
+	}
+	if rp.Stable > 0 && rp.nwin >= rp.Stable {
+		rp.Cycle = cyc
+		return true
+	}
+	return false
+}
+
+// RT returns the settling time, in cycles, given the number of minus phase
+// cycles of the trial, which is returned if it has not settled
+func (rp *RTParams) RT(ncyc int) int {
+	if rp.Cycle < 0 {
+		return ncyc
+	}
+	return rp.Cycle + 1
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSelectArgMax(t *testing.T) {
+	tests := []struct {
+		name   string
+		acts   []float32
+		minAct float32
+		want   int
+	}{
+		{"most active", []float32{0.2, 0.9, 0.5}, 0.1, 1},
+		{"first of ties", []float32{0.7, 0.7, 0.1}, 0.1, 0},
+		{"below MinAct", []float32{0.05, 0.02}, 0.1, -1},
+		{"no Behaviors", nil, 0.1, -1},
+	}
+	for _, tt := range tests {
+		sp := &SelectParams{}
+		sp.Defaults()
+		sp.MinAct = tt.minAct
+		if got := sp.Select(tt.acts); got != tt.want {
+			t.Errorf("%v: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// selectFreqs returns the frequency of each Behavior over n selections
+func selectFreqs(sp *SelectParams, acts []float32, n int) []float64 {
+	freqs := make([]float64, len(acts))
+	for i := 0; i < n; i++ {
+		if b := sp.Select(acts); b >= 0 {
+			freqs[b] += 1 / float64(n)
+		}
+	}
+	return freqs
+}
+
+func TestSelectSoftMax(t *testing.T) {
+	acts := []float32{0.2, 0.5, 0.9, 0.6}
+	tests := []struct {
+		name string
+		temp float32
+	}{
+		{"low temperature", 0.1},
+		{"default temperature", 0.2},
+		{"high temperature", 1},
+	}
+	n := 20000
+	for _, tt := range tests {
+		sp := &SelectParams{}
+		sp.Defaults()
+		sp.Policy = SoftMax
+		sp.Temp = tt.temp
+		var sum float64
+		want := make([]float64, len(acts))
+		for i, a := range acts {
+			want[i] = math.Exp(float64(a / tt.temp))
+			sum += want[i]
+		}
+		for i, f := range selectFreqs(sp, acts, n) {
+			want[i] /= sum
+			if math.Abs(f-want[i]) > 0.02 {
+				t.Errorf("%v: Behavior %v: got frequency %.3f, want %.3f", tt.name, i, f, want[i])
+			}
+		}
+	}
+}
+
+func TestSelectSoftMaxZeroTemp(t *testing.T) {
+	for _, temp := range []float32{0, -1} {
+		sp := &SelectParams{}
+		sp.Defaults()
+		sp.Policy = SoftMax
+		sp.Temp = temp
+		for i := 0; i < 100; i++ {
+			if got := sp.Select([]float32{0.2, 0.8, 0.5}); got != 1 {
+				t.Fatalf("Temp %v: got %v, want the most active: 1", temp, got)
+			}
+		}
+	}
+}
+
+func TestSelectEpsGreedy(t *testing.T) {
+	acts := []float32{0.2, 0.9, 0.5, 0.1}
+	tests := []float32{0, 0.1, 0.5, 1}
+	n := 20000
+	for _, eps := range tests {
+		sp := &SelectParams{}
+		sp.Defaults()
+		sp.Policy = EpsGreedy
+		sp.Epsilon = eps
+		for i, f := range selectFreqs(sp, acts, n) {
+			want := float64(eps) / float64(len(acts)) // random choice
+			if i == 1 {
+				want += 1 - float64(eps)
+			}
+			if math.Abs(f-want) > 0.02 {
+				t.Errorf("Epsilon %v: Behavior %v: got frequency %.3f, want %.3f", eps, i, f, want)
+			}
+		}
+	}
+}
+
+func TestSelectSeed(t *testing.T) {
+	acts := []float32{0.3, 0.5, 0.4}
+	seq := func(seed int64) []int {
+		sp := &SelectParams{}
+		sp.Defaults()
+		sp.Policy = SoftMax
+		sp.Temp = 0.5
+		sp.Seed(seed)
+		s := make([]int, 50)
+		for i := range s {
+			s[i] = sp.Select(acts)
+		}
+		return s
+	}
+	a, b := seq(3), seq(3)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("the same seed selected different Behaviors at %v: %v vs. %v", i, a[i], b[i])
+		}
+	}
+}
+
+func TestSelectValidate(t *testing.T) {
+	tests := []struct {
+		policy  SelPolicies
+		temp    float32
+		epsilon float32
+		valid   bool
+	}{
+		{ArgMax, 0, 0, true},
+		{SoftMax, 0.1, 0, true},
+		{SoftMax, 0, 0, false},
+		{SoftMax, -0.1, 0, false},
+		{EpsGreedy, 0, 0.1, true},
+		{EpsGreedy, 0, 1, true},
+		{EpsGreedy, 0, 1.5, false},
+		{EpsGreedy, 0, -0.1, false},
+	}
+	for _, tt := range tests {
+		sp := &SelectParams{Policy: tt.policy, Temp: tt.temp, Epsilon: tt.epsilon}
+		if err := sp.Validate(); (err == nil) != tt.valid {
+			t.Errorf("%v Temp %v Epsilon %v: got error %v, want valid: %v", tt.policy, tt.temp, tt.epsilon, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/emer/emergent/estats"
+)
+
+// SymptomNames are the names of the depression-like symptom scores, which are
+// also the names of their stats and log items, with the composite Symptoms last
+var SymptomNames = []string{"Anhedonia", "Avolition", "Withdrawal", "SleepDist", "Symptoms"}
+
+// The symptoms, as indexes into SymptomNames
+const (
+	Anhedonia = iota
+	Avolition
+	Withdrawal
+	SleepDist
+	NSymptoms
+)
+
+// SymptomParams are the parameters of the depression-like symptom scores,
+// computed from the network and World state on each trial or time step, and
+// averaged over each epoch, or over each simulated day in the World:
+//   - Anhedonia: 1 - the most active Approach unit, on trials with a rewarding cue
+//   - Avolition: inactivity of the Behaviors, weighted by their Cost: effortful
+//     Behaviors are not engaged
+//   - Withdrawal: 1 - the most active Social Behavior
+//   - SleepDist: sleep disturbance, in the World only: 1 - the most active Sleep
+//     Behavior at night, or the most active Sleep Behavior during the day
+//
+// The composite Symptoms score is the weighted mean of the scores that apply.
+type SymptomParams struct {
+	RewardUnits []int              `desc:"indexes of the EnviroFeatures units that are rewarding cues (Frnd, Lbry, Food, Mate)"`
+	CueThr      float32            `def:"0.5" desc:"activity of a rewarding cue above which Anhedonia is scored"`
+	Social      []int              `desc:"indexes of the social Behaviors (Hngt, SHngt)"`
+	Sleep       []int              `desc:"indexes of the sleep Behaviors (Sleep, SSlp)"`
+	NightStart  float32            `def:"22" desc:"hour of the day when the night starts"`
+	NightEnd    float32            `def:"6" desc:"hour of the day when the night ends"`
+	Wts         [NSymptoms]float32 `desc:"weight of each symptom (Anhedonia, Avolition, Withdrawal, SleepDist) in the composite Symptoms score"`
+	Train       SymptomSums        `view:"-" desc:"sums over the current training epoch"`
+	Test        SymptomSums        `view:"-" desc:"sums over the current testing epoch"`
+	Day         SymptomSums        `view:"-" desc:"sums over the current day of the World"`
+}
+
+func (sp *SymptomParams) Defaults() {
+	sp.RewardUnits = []int{0, 1, 2, 3}
+	sp.CueThr = 0.5
+	sp.Social = []int{0, 1}
+	sp.Sleep = []int{8, 9}
+	sp.NightStart = 22
+	sp.NightEnd = 6
+	sp.Wts = [NSymptoms]float32{1, 1, 1, 1}
+}
+
+// SymptomSums are the sums of each symptom score over the trials or time steps
+// where it applies, and their number
+type SymptomSums struct {
+	Sum [NSymptoms]float64
+	N   [NSymptoms]int
+}
+
+// Reset sets the sums back to zero
+func (sm *SymptomSums) Reset() {
+	*sm = SymptomSums{}
+}
+
+// Add adds the score of the given symptom
+func (sm *SymptomSums) Add(sym int, score float32) {
+	sm.Sum[sym] += float64(score)
+	sm.N[sym]++
+}
+
+// Step adds the symptom scores for the activities of the EnviroFeatures,
+// Approach, Behavior and Cost layers on one trial or time step to the sums,
+// with the hour of the day in the World, or -1 if there is no clock
+func (sp *SymptomParams) Step(sm *SymptomSums, enviro, appr, beh, cost []float32, hour float32) {
+	if maxAct(enviro, sp.RewardUnits) > sp.CueThr {
+		sm.Add(Anhedonia, 1-maxAct(appr, nil))
+	}
+	var ctot, inact float32
+	for i, c := range cost {
+		if i < len(beh) && c > 0 {
+			ctot += c
+			inact += c * (1 - beh[i])
+		}
+	}
+	if ctot > 0 {
+		sm.Add(Avolition, inact/ctot)
+	}
+	if len(sp.Social) > 0 {
+		sm.Add(Withdrawal, 1-maxAct(beh, sp.Social))
+	}
+	if hour >= 0 && len(sp.Sleep) > 0 {
+		slp := maxAct(beh, sp.Sleep)
+		if HourIn(hour, sp.NightStart, sp.NightEnd) {
+			sm.Add(SleepDist, 1-slp)
+		} else {
+			sm.Add(SleepDist, slp)
+		}
+	}
+}
+
+// Scores returns the mean of each symptom score over the sums, 0 if it
+// did not apply, and the composite Symptoms score last
+func (sp *SymptomParams) Scores(sm *SymptomSums) []float64 {
+	scs := make([]float64, NSymptoms+1)
+	var comp, wtot float64
+	for sym := 0; sym < NSymptoms; sym++ {
+		if sm.N[sym] == 0 {
+			continue
+		}
+		scs[sym] = sm.Sum[sym] / float64(sm.N[sym])
+		comp += float64(sp.Wts[sym]) * scs[sym]
+		wtot += float64(sp.Wts[sym])
+	}
+	if wtot > 0 {
+		scs[NSymptoms] = comp / wtot
+	}
+	return scs
+}
+
+// SetStats sets the stat of each score, named by SymptomNames, from the sums
+func (sp *SymptomParams) SetStats(stats *estats.Stats, sm *SymptomSums) {
+	for i, sc := range sp.Scores(sm) {
+		stats.SetFloat(SymptomNames[i], sc)
+	}
+}
+
+// maxAct returns the maximum activity over the given units, or all if nil
+func maxAct(acts []float32, units []int) float32 {
+	var max float32
+	if units == nil {
+		for _, a := range acts {
+			if a > max {
+				max = a
+			}
+		}
+		return max
+	}
+	for _, ui := range units {
+		if ui < len(acts) && acts[ui] > max {
+			max = acts[ui]
+		}
+	}
+	return max
+}
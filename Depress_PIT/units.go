@@ -0,0 +1,90 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/etable/etable"
+)
+
+// UnitLabels is a registry of the names of the units of each layer, e.g., the
+// EnviroFeatures (Frnd, Lbry, Food...) and the Behaviors (Hngt, SHngt, Stdy...),
+// which are used in place of unit indexes in the logs and console output.
+// It is read from a table (e.g., UnitLabels.tsv) with one row per layer, and
+// these columns:
+//   - Layer: name of the layer
+//   - Labels: names of its units, in order, separated by spaces
+//
+// or from the "labels" metadata of the columns of a pattern table, with the
+// names separated by spaces in the same way.
+type UnitLabels map[string][]string
+
+// SetTable sets the labels of the layers from the rows of the given table
+func (ul UnitLabels) SetTable(dt *etable.Table) error {
+	for _, cnm := range []string{"Layer", "Labels"} {
+		if dt.ColByName(cnm) == nil {
+			return fmt.Errorf("UnitLabels: table has no %v column", cnm)
+		}
+	}
+	for row := 0; row < dt.Rows; row++ {
+		ul[dt.CellString("Layer", row)] = strings.Fields(dt.CellString("Labels", row))
+	}
+	return nil
+}
+
+// SetColMeta sets the labels of the layers that have none from the "labels"
+// metadata of the columns of the given pattern table, named by layer
+func (ul UnitLabels) SetColMeta(dt *etable.Table) {
+	for ci, cl := range dt.Cols {
+		lnm := dt.ColNames[ci]
+		if _, has := ul[lnm]; has {
+			continue
+		}
+		if labs, has := cl.MetaData("labels"); has {
+			ul[lnm] = strings.Fields(labs)
+		}
+	}
+}
+
+// Validate checks that the labels of each layer in the network match its
+// number of units, removing those that do not
+func (ul UnitLabels) Validate(net emer.Network) error {
+	var errs []string
+	for lnm, labs := range ul {
+		ly, err := net.LayerByNameTry(lnm)
+		if err != nil {
+			continue
+		}
+		if n := ly.Shape().Len(); len(labs) != n {
+			errs = append(errs, fmt.Sprintf("%v has %v labels but %v units", lnm, len(labs), n))
+			delete(ul, lnm)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("UnitLabels: %v", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// Label returns the label of given unit of the layer -- empty if none
+func (ul UnitLabels) Label(lnm string, ui int) string {
+	labs := ul[lnm]
+	if ui < 0 || ui >= len(labs) {
+		return ""
+	}
+	return labs[ui]
+}
+
+// UnitName returns the name of given unit of the layer, e.g., Behavior_Eat,
+// or its index, e.g., Behavior[4], if it has no label
+func (ul UnitLabels) UnitName(lnm string, ui int) string {
+	if lab := ul.Label(lnm, ui); lab != "" {
+		return lnm + "_" + lab
+	}
+	return fmt.Sprintf("%v[%d]", lnm, ui)
+}
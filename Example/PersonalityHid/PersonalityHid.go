@@ -202,9 +202,11 @@ type Sim struct {
 	Net          *leabra.Network   `view:"no-inline" desc:"the network -- click to view / edit parameters for layers, prjns, etc"`
 	Instr        *etable.Table     `view:"no-inline" desc:"Training pattern for Instrumental Learning"`
 	Pvlv         *etable.Table     `view:"no-inline" desc:"Training pattern for Pavlovian Learning"`
-	Trn    		 *etable.Table     `view:"no-inline" desc:"Table that controls type of training and number of Epochs of training"`
+	Trn    		 *etable.Table     `view:"no-inline" desc:"Table of the phases of the training curriculum: pattern table, layer types, lesions and number of Epochs of training for each"`
+	PhasePats    map[string]*etable.Table `view:"-" desc:"pattern tables of the curriculum phases, by file name"`
+	Ckpts        Checkpoints       `desc:"weights checkpoints saved at the start of each run and at the end of each phase of the curriculum"`
 	TestData 	 *etable.Table     `view:"no-inline" desc:"Table for the Test data file"`
-	Training	 string			   `view:"no-inline" desc:"Type of training: Pavlovian or Instrumental"`
+	Training	 string			   `view:"no-inline" desc:"name of the current phase of training (e.g., PAVLOV or INSTRUMENTAL)"`
 	TrnEpcLog    *etable.Table     `view:"no-inline" desc:"training epoch-level log data"`
 	TstEpcLog    *etable.Table     `view:"no-inline" desc:"testing epoch-level log data"`
 	TstTrlLog    *etable.Table     `view:"no-inline" desc:"testing trial-level log data"`
@@ -281,6 +283,7 @@ func (ss *Sim) New() {
 	ss.Instr = &etable.Table{}
 	ss.Pvlv = &etable.Table{}
 	ss.Trn = &etable.Table{}
+	ss.PhasePats = make(map[string]*etable.Table)
 	ss.TestData = &etable.Table{}
 	ss.TrnEpcLog = &etable.Table{}
 	ss.TstEpcLog = &etable.Table{}
@@ -432,6 +435,9 @@ func (ss *Sim) Init() {
 	// selected or patterns have been modified etc
 	ss.StopNow = false
 	ss.SetParams("", ss.LogSetParams) // all sheets
+	if err := ss.Ckpts.Open(); err != nil {
+		log.Println(err)
+	}
 	ss.NewRun()
 	ss.UpdateView(true)
 }
@@ -604,7 +610,9 @@ func (ss *Sim) NewRun() {
 	ss.TestEnv.Init(run)
 	ss.Time.Reset()
 	ss.Net.InitWts()
-	ss.Net.SaveWtsJSON("trained.wts")
+	if ss.Ckpts.Start == "" { // not needed when resuming from a later checkpoint
+		ss.SaveCheckpoint(run, 0, InitPhase)
+	}
 	ss.InitStats()
 	ss.TrnEpcLog.SetNumRows(0)
 	ss.TstEpcLog.SetNumRows(0)
@@ -723,143 +731,92 @@ func (ss *Sim) TrialFieldUpdates() {
    }
 }
 
+// TrainPIT trains the network on all the phases of the curriculum in the Trn
+// table in order.  The first phase starts from the Ckpts.Start checkpoint, or
+// else the INIT checkpoint of the run, and each next phase from the checkpoint
+// saved at the end of the previous one, unless it has its own Start.
 func (ss *Sim) TrainPIT() {
+	phases, err := CurricPhases(ss.Trn)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	for _, ph := range phases {
+		if err := ph.Validate(ss.Net); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+	run := ss.TrainEnv.Run.Cur
+	prv := ss.Ckpts.Start
+	if prv == "" {
+		prv = ss.Ckpts.Latest(run, InitPhase)
+	}
+	for _, ph := range phases {
+		start := prv
+		if ph.Start != "" {
+			start = ss.Ckpts.Find(run, ph.Start)
+		}
+		prv, err = ss.TrainPhase(ph, run, start)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+	}
+}
 
-for i := 0; i < 2; i++ {
-		
-ss.Training = ss.Trn.CellString("Training", i)
-ss.MaxEpcs = int(ss.Trn.CellFloat("MaxEpoch", i))
-
-
-
-switch ss.Training {
-
-	case "INSTRUMENTAL":
-
-	ss.TrainEnv.Epoch.Cur = 0  //set current epoch to 0 so that training starts from 0 epochs
-	
-	// Need to set up training patterns.  	
-	ss.TrainEnv.Table = etable.NewIdxView(ss.Instr)
-	ss.TestEnv.Table = etable.NewIdxView(ss.Instr)
-	
-	run := ss.TrainEnv.Run.Cur  //  Is Init what is resetting the TrainEnv table index properly?
-	ss.TrainEnv.Init(run)
-
-	
-	ss.TrialFieldUpdates()	
-
-
-
-// Unlesion Hidden and Behavior layer to make sure all layers are unlesioned
-	ss.Net.LayerByName("Hidden").SetOff(false)
-	ss.Net.LayerByName("Behavior").SetOff(false)
-
-// Load saved weights
-// OpenWtsJSON opens trained weights
-		ss.Net.OpenWtsJSON("trained.wts")
-	
-
-// Define Environment and InteroState as Compare layers
-// Define Approach and Avoid as Input layers
-// Define Behavior as a Target layer
-	ss.Net.LayerByName("Environment").SetType(emer.Input)
-	ss.Net.LayerByName("InteroState").SetType(emer.Input)
-	ss.Net.LayerByName("Approach").SetType(emer.Input)
-	ss.Net.LayerByName("Avoid").SetType(emer.Input)
-	ss.Net.LayerByName("Behavior").SetType(emer.Target)
-	
-
-// Lesion Environment, InteroState, and Hidden2 layers
-	ss.Net.LayerByName("Environment").SetOff(true)
-	ss.Net.LayerByName("InteroState").SetOff(true)
-	ss.Net.LayerByName("Hidden2").SetOff(true)
-
-	ss.Train()
-
-
-// Unlesion Environment and InteroState layers
-	ss.Net.LayerByName("Environment").SetOff(false)
-	ss.Net.LayerByName("InteroState").SetOff(false)
-	ss.Net.LayerByName("Hidden2").SetOff(false)
-
-// Save weights
-	ss.Net.SaveWtsJSON("trained.wts")
-
-
-
-// Define Environment and InteroState as Input layers
-// Define Approach and Avoid as Target layers
-// Define Behavior as a Target layer
-// Makes sure that layers are set to default.
-	ss.Net.LayerByName("Environment").SetType(emer.Input)
-	ss.Net.LayerByName("InteroState").SetType(emer.Input)
-	ss.Net.LayerByName("Approach").SetType(emer.Target)
-	ss.Net.LayerByName("Avoid").SetType(emer.Target)	
-	ss.Net.LayerByName("Behavior").SetType(emer.Target)
-
-	case "PAVLOV":
-
-	ss.TrainEnv.Epoch.Cur = 0  //set current epoch to 0 so that training starts from 0 epochs
-	
-// Pavlovian Training
-
-// Need to set up training patterns  
+// TrainPhase trains the network on one phase of the curriculum, with its own
+// patterns, layer types, lesions and stopping criteria, starting from the
+// start checkpoint (current weights if empty).  Returns the checkpoint
+// saved at the end of the phase.
+func (ss *Sim) TrainPhase(ph *CurricPhase, run int, start string) (string, error) {
+	pats, err := ss.PhasePatterns(ph.Patterns)
+	if err != nil {
+		return "", err
+	}
+	ss.Training = ph.Name
+	ss.MaxEpcs = ph.MaxEpcs
+	ss.NZeroStop = ph.NZeroStop
 
-	ss.TrainEnv.Table = etable.NewIdxView(ss.Pvlv)
-	ss.TestEnv.Table = etable.NewIdxView(ss.Pvlv)	
-	
-	run := ss.TrainEnv.Run.Cur  //  Is Init what is resetting the TrainEnv table index properly?
+	ss.TrainEnv.Epoch.Cur = 0 //set current epoch to 0 so that training starts from 0 epochs
+	ss.TrainEnv.Table = etable.NewIdxView(pats)
+	ss.TestEnv.Table = etable.NewIdxView(pats)
 	ss.TrainEnv.Init(run)
-
 	ss.TrialFieldUpdates()
 
+	if start != "" {
+		if err := ss.Net.OpenWtsJSON(gi.FileName(start)); err != nil {
+			return "", err
+		}
+	}
+	types := ph.Apply(ss.Net)
+	ss.TrainRunPhase(run)
+	ph.Restore(ss.Net, types)
+	return ss.SaveCheckpoint(run, ss.TrainEnv.Epoch.Cur, ph.Name), nil
+}
 
-
-// Define Environment and InteroState as Input layers
-// Define Approach and Avoid as Target layers
-// Define Behavior as a Compare layer
-	ss.Net.LayerByName("Environment").SetType(emer.Input)
-	ss.Net.LayerByName("InteroState").SetType(emer.Input)
-	ss.Net.LayerByName("Approach").SetType(emer.Target)
-	ss.Net.LayerByName("Avoid").SetType(emer.Target)
-	ss.Net.LayerByName("Behavior").SetType(emer.Target)
-
-// Load saved weights
-		ss.Net.OpenWtsJSON("trained.wts")
-	
-
-// Lesion Hidden layer and Behavior layer
-	ss.Net.LayerByName("Hidden").SetOff(true)
-	ss.Net.LayerByName("Behavior").SetOff(true)
-
-
-// Train until number of Epochs of training reached
+// TrainRunPhase trains the current phase of the curriculum until its stop
+// criteria, within the given run only -- the next phase continues the run
+func (ss *Sim) TrainRunPhase(run int) {
+	mx := ss.TrainEnv.Run.Max
+	ss.TrainEnv.Run.Max = run + 1 // stop at the end of the phase
+	ss.NeedsNewRun = false
 	ss.Train()
+	ss.TrainEnv.Run.Max = mx
+}
 
-//Unlesion Hidden Layer and Behavior layer
-	ss.Net.LayerByName("Hidden").SetOff(false)
-	ss.Net.LayerByName("Behavior").SetOff(false)
-
-
-// Save weights
-	ss.Net.SaveWtsJSON("trained.wts")
-	
-	
-// Define Environment and InteroState as Input layers
-// Define Approach and Avoid as Target layers
-// Define Behavior as a Target layer
-// Makes sure that layers are set to default.
-
-	ss.Net.LayerByName("Environment").SetType(emer.Input)
-	ss.Net.LayerByName("InteroState").SetType(emer.Input)
-	ss.Net.LayerByName("Approach").SetType(emer.Target)
-	ss.Net.LayerByName("Avoid").SetType(emer.Target)	
-	ss.Net.LayerByName("Behavior").SetType(emer.Target)
-
-	
-	
-		}
+// PhasePatterns returns the pattern table of the given file name,
+// opening it the first time it is used by a phase of the curriculum
+func (ss *Sim) PhasePatterns(fnm string) (*etable.Table, error) {
+	if dt, has := ss.PhasePats[fnm]; has {
+		return dt, nil
+	}
+	dt := &etable.Table{}
+	if err := dt.OpenCSV(gi.FileName(fnm), etable.Tab); err != nil {
+		return nil, err
 	}
+	ss.PhasePats[fnm] = dt
+	return dt, nil
 }
 
 
@@ -1061,6 +1018,22 @@ func (ss *Sim) WeightsFileName() string {
 	return ss.Net.Nm + "_" + ss.RunName() + "_" + ss.RunEpochName(ss.TrainEnv.Run.Cur, ss.TrainEnv.Epoch.Cur) + ".wts"
 }
 
+// CheckpointFileName returns the weights file name for a checkpoint of the
+// given phase, which is the WeightsFileName with the phase added
+func (ss *Sim) CheckpointFileName(run, epc int, phase string) string {
+	return ss.Net.Nm + "_" + ss.RunName() + "_" + ss.RunEpochName(run, epc) + "_" + phase + ".wts"
+}
+
+// SaveCheckpoint saves the current weights as a checkpoint of the given phase,
+// and returns its file
+func (ss *Sim) SaveCheckpoint(run, epc int, phase string) string {
+	fnm, err := ss.Ckpts.Save(ss.Net, ss.CheckpointFileName(run, epc, phase), run, phase, epc, ss.ParamsName())
+	if err != nil {
+		log.Println(err)
+	}
+	return fnm
+}
+
 // LogFileName returns default log file name
 func (ss *Sim) LogFileName(lognm string) string {
 	return ss.Net.Nm + "_" + ss.RunName() + "_" + lognm + ".csv"
@@ -1831,6 +1804,7 @@ func (ss *Sim) CmdArgs() {
 	flag.BoolVar(&ss.SaveWts, "wts", false, "if true, save final weights after each run")
 	flag.BoolVar(&saveEpcLog, "epclog", true, "if true, save train epoch log to file")
 	flag.BoolVar(&saveRunLog, "runlog", true, "if true, save run epoch log to file")
+	flag.StringVar(&ss.Ckpts.Dir, "ckpt-dir", "", "directory for the weights checkpoints and their manifest, checkpoints.tsv")
 	flag.BoolVar(&nogui, "nogui", true, "if not passing any other args and want to run nogui, use nogui")
 	flag.Parse()
 	ss.Init()
@@ -0,0 +1,119 @@
+// Copyright (c) 2019, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/emer/emergent/emer"
+	"github.com/emer/etable/etable"
+)
+
+// CurricPhase is one phase of a training curriculum, read from one row of a
+// curriculum table (e.g., InstrThenPvlv.tsv), which has these columns:
+//   - Training: name of the phase (e.g., INSTRUMENTAL, PAVLOV, EXTINCTION)
+//   - Patterns: file name of the pattern table to train and test on
+//   - MaxEpoch: maximum number of epochs of training
+//   - NZeroStop: stop after this many epochs with zero SSE -- 0 or less = no early stop (optional)
+//   - Inputs: space-separated names of the layers that are Input (optional)
+//   - Targets: space-separated names of the layers that are Target (optional)
+//   - Lesion: space-separated names of the layers that are lesioned (optional)
+//   - Start: checkpoint that the phase starts from: the name of an earlier phase,
+//     INIT or a weights file -- empty = the end of the previous phase (optional)
+//
+// Layers not named in Inputs or Targets keep their type, and the layer types
+// and lesions are restored at the end of the phase.
+type CurricPhase struct {
+	Name      string   `desc:"name of the phase"`
+	Patterns  string   `desc:"file name of the pattern table to train and test on"`
+	MaxEpcs   int      `desc:"maximum number of epochs of training"`
+	NZeroStop int      `desc:"if a positive number, training will stop after this many epochs with zero SSE"`
+	Inputs    []string `desc:"names of the layers that are Input during the phase"`
+	Targets   []string `desc:"names of the layers that are Target during the phase"`
+	Lesion    []string `desc:"names of the layers that are lesioned during the phase"`
+	Start     string   `desc:"checkpoint that the phase starts from -- empty = the end of the previous phase"`
+}
+
+// CurricPhases returns the phases in all the rows of the curriculum table, in order
+func CurricPhases(dt *etable.Table) ([]*CurricPhase, error) {
+	for _, cnm := range []string{"Training", "Patterns", "MaxEpoch"} {
+		if dt.ColByName(cnm) == nil {
+			return nil, fmt.Errorf("CurricPhases: curriculum table has no %v column", cnm)
+		}
+	}
+	lays := func(cnm string, row int) []string {
+		if dt.ColByName(cnm) == nil {
+			return nil
+		}
+		return strings.Fields(dt.CellString(cnm, row))
+	}
+	phases := make([]*CurricPhase, dt.Rows)
+	for row := 0; row < dt.Rows; row++ {
+		cp := &CurricPhase{}
+		cp.Name = dt.CellString("Training", row)
+		cp.Patterns = dt.CellString("Patterns", row)
+		cp.MaxEpcs = int(dt.CellFloat("MaxEpoch", row))
+		cp.NZeroStop = -1
+		if dt.ColByName("NZeroStop") != nil {
+			cp.NZeroStop = int(dt.CellFloat("NZeroStop", row))
+		}
+		cp.Inputs = lays("Inputs", row)
+		cp.Targets = lays("Targets", row)
+		cp.Lesion = lays("Lesion", row)
+		if dt.ColByName("Start") != nil {
+			cp.Start = dt.CellString("Start", row)
+		}
+		if cp.Patterns == "" {
+			return nil, fmt.Errorf("CurricPhases: phase %v in row %v has no Patterns", cp.Name, row)
+		}
+		phases[row] = cp
+	}
+	return phases, nil
+}
+
+// Validate checks that all the layers named in the phase exist in the network
+func (cp *CurricPhase) Validate(net emer.Network) error {
+	for _, lnms := range [][]string{cp.Inputs, cp.Targets, cp.Lesion} {
+		for _, lnm := range lnms {
+			if _, err := net.LayerByNameTry(lnm); err != nil {
+				return fmt.Errorf("CurricPhase %v: %v", cp.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Apply sets the layer types and lesions of the phase on the network,
+// and returns the layer types before, for Restore
+func (cp *CurricPhase) Apply(net emer.Network) map[string]emer.LayerType {
+	types := make(map[string]emer.LayerType)
+	for _, lnm := range cp.Inputs {
+		ly := net.LayerByName(lnm)
+		types[lnm] = ly.Type()
+		ly.SetType(emer.Input)
+	}
+	for _, lnm := range cp.Targets {
+		ly := net.LayerByName(lnm)
+		if _, has := types[lnm]; !has {
+			types[lnm] = ly.Type()
+		}
+		ly.SetType(emer.Target)
+	}
+	for _, lnm := range cp.Lesion {
+		net.LayerByName(lnm).SetOff(true)
+	}
+	return types
+}
+
+// Restore un-lesions the layers lesioned by Apply, and sets back the layer types
+func (cp *CurricPhase) Restore(net emer.Network, types map[string]emer.LayerType) {
+	for _, lnm := range cp.Lesion {
+		net.LayerByName(lnm).SetOff(false)
+	}
+	for lnm, typ := range types {
+		net.LayerByName(lnm).SetType(typ)
+	}
+}